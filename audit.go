@@ -0,0 +1,148 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"sync"
+	"time"
+)
+
+// Sources of the values recorded in an AuditEntry.
+const (
+	AuditSourceEnvironment = "environment"
+	AuditSourceDefault     = "default"
+	AuditSourceNone        = "none"
+)
+
+// AuditEntry records a single lookup done through an AuditGetter.
+type AuditEntry struct {
+	// Name is the name of the environment variable.
+	Name string
+	// Found reports whether the variable was set to a non-empty value.
+	Found bool
+	// Source is one of AuditSourceEnvironment, AuditSourceDefault or
+	// AuditSourceNone.
+	Source string
+	// Timestamp is the time of the lookup.
+	Timestamp time.Time
+	// Value is the effective value. For secrets, it is masked by "*".
+	Value string
+	// Secret reports whether the variable was read as secret.
+	Secret bool
+}
+
+// AuditGetter wraps the getters of this package and records every lookup in
+// an in-memory audit log. Values of secrets are never recorded, only the fact
+// that they were read. An AuditGetter is safe for concurrent use.
+type AuditGetter struct {
+	mu      sync.Mutex
+	entries []AuditEntry
+}
+
+// NewAuditGetter creates an AuditGetter with an empty audit log.
+func NewAuditGetter() *AuditGetter {
+	return &AuditGetter{}
+}
+
+// AuditLog returns a copy of all entries recorded so far, in lookup order.
+func (a *AuditGetter) AuditLog() []AuditEntry {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entries := make([]AuditEntry, len(a.entries))
+	copy(entries, a.entries)
+	return entries
+}
+
+// GetEnvOrWarn calls GetEnvOrWarn and records the lookup.
+func (a *AuditGetter) GetEnvOrWarn(envName string) string {
+	val := GetEnvOrWarn(envName)
+	a.recordLookup(envName, val, false)
+	return val
+}
+
+// GetEnvSecretOrWarn calls GetEnvSecretOrWarn and records the lookup.
+func (a *AuditGetter) GetEnvSecretOrWarn(envName string) string {
+	val := GetEnvSecretOrWarn(envName)
+	a.recordLookup(envName, val, true)
+	return val
+}
+
+// GetEnvOrDefault calls GetEnvOrDefault and records the lookup.
+func (a *AuditGetter) GetEnvOrDefault(envName string, defaultValue string) string {
+	raw, _ := peekEnv(envName)
+	found := len(raw) != 0
+	val := GetEnvOrDefault(envName, defaultValue)
+	if !found {
+		a.record(AuditEntry{Name: envName, Source: AuditSourceDefault, Value: val})
+		return val
+	}
+	a.recordLookup(envName, val, false)
+	return val
+}
+
+// GetEnvOrFail calls GetEnvOrFail and records the lookup.
+func (a *AuditGetter) GetEnvOrFail(envName string) (string, error) {
+	val, err := GetEnvOrFail(envName)
+	a.recordLookup(envName, val, false)
+	return val, err
+}
+
+// GetEnvSecretOrFail calls GetEnvSecretOrFail and records the lookup.
+func (a *AuditGetter) GetEnvSecretOrFail(envName string) (string, error) {
+	val, err := GetEnvSecretOrFail(envName)
+	a.recordLookup(envName, val, true)
+	return val, err
+}
+
+// GetEnvOrPanic records the lookup and calls GetEnvOrPanic.
+func (a *AuditGetter) GetEnvOrPanic(envName string) string {
+	val, _ := peekEnv(envName)
+	a.recordLookup(envName, val, false)
+	return GetEnvOrPanic(envName)
+}
+
+// GetEnvSecretOrPanic records the lookup and calls GetEnvSecretOrPanic.
+func (a *AuditGetter) GetEnvSecretOrPanic(envName string) string {
+	val, _ := peekEnv(envName)
+	a.recordLookup(envName, val, true)
+	return GetEnvSecretOrPanic(envName)
+}
+
+// recordLookup records the lookup of a variable that has no default.
+func (a *AuditGetter) recordLookup(envName string, val string, secret bool) {
+	entry := AuditEntry{
+		Name:   envName,
+		Found:  len(val) != 0,
+		Source: AuditSourceNone,
+		Secret: secret,
+	}
+	if entry.Found {
+		entry.Source = AuditSourceEnvironment
+		entry.Value = val
+		if secret {
+			entry.Value = secretMask
+		}
+	}
+	a.record(entry)
+}
+
+// record appends the entry to the audit log, setting its timestamp.
+func (a *AuditGetter) record(entry AuditEntry) {
+	entry.Timestamp = time.Now()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.entries = append(a.entries, entry)
+}
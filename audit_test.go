@@ -0,0 +1,98 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const otherVarName = "SOME_OTHER_ARBITRARY_TEST_ENV_VAR_NAME"
+
+func TestAuditGetter_RecordsLookupsInOrder(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(otherVarName, "")
+	before := time.Now()
+
+	audit := NewAuditGetter()
+	audit.GetEnvOrWarn(envVarName)
+	audit.GetEnvOrDefault(otherVarName, "Default Value")
+	_, err := audit.GetEnvOrFail(otherVarName)
+	assert.Error(t, err)
+
+	entries := audit.AuditLog()
+	assert.Len(t, entries, 3)
+	assert.Equal(t, AuditEntry{
+		Name:      envVarName,
+		Found:     true,
+		Source:    AuditSourceEnvironment,
+		Timestamp: entries[0].Timestamp,
+		Value:     expectedValue,
+	}, entries[0])
+	assert.Equal(t, AuditEntry{
+		Name:      otherVarName,
+		Source:    AuditSourceDefault,
+		Timestamp: entries[1].Timestamp,
+		Value:     "Default Value",
+	}, entries[1])
+	assert.Equal(t, AuditEntry{
+		Name:      otherVarName,
+		Source:    AuditSourceNone,
+		Timestamp: entries[2].Timestamp,
+	}, entries[2])
+	assert.False(t, entries[0].Timestamp.Before(before))
+}
+
+func TestAuditGetter_NeverRecordsSecretValues(t *testing.T) {
+	t.Setenv(envVarName, "s3cr3t")
+
+	audit := NewAuditGetter()
+	audit.GetEnvSecretOrWarn(envVarName)
+	_, err := audit.GetEnvSecretOrFail(envVarName)
+	assert.NoError(t, err)
+	audit.GetEnvSecretOrPanic(envVarName)
+
+	for _, entry := range audit.AuditLog() {
+		assert.True(t, entry.Found)
+		assert.True(t, entry.Secret)
+		assert.Equal(t, "**********", entry.Value)
+	}
+}
+
+func TestAuditGetter_RecordsLookupBeforePanicking(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	audit := NewAuditGetter()
+	assert.Panics(t, func() {
+		audit.GetEnvOrPanic(envVarName)
+	})
+
+	entries := audit.AuditLog()
+	assert.Len(t, entries, 1)
+	assert.False(t, entries[0].Found)
+}
+
+func TestAuditGetter_AuditLogReturnsCopy(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	audit := NewAuditGetter()
+	audit.GetEnvOrWarn(envVarName)
+	audit.AuditLog()[0].Value = "tampered"
+
+	assert.Equal(t, expectedValue, audit.AuditLog()[0].Value)
+}
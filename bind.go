@@ -0,0 +1,163 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct tags evaluated by Bind.
+const (
+	envTag      = "env"
+	defaultTag  = "default"
+	requiredTag = "required"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Bind populates the fields of the struct target points to from environment
+// variables. The name of the variable is given by the tag `env:"VAR_NAME"`,
+// fields without it as well as unexported fields are skipped. Supported field
+// types are string, bool, all signed integer types, float32, float64 and
+// time.Duration. Booleans accept the spellings of GetEnvBoolOrDefault.
+//
+// If a variable is not set or empty, the value of the tag `default:"..."` is
+// used. Without default, the field keeps its current value, unless the tag
+// `required:"true"` is given, which makes the variable mandatory.
+// An error is returned if target is not a non-nil pointer to a struct, if a
+// field has an unsupported type or if any variable is missing or invalid. In
+// the latter case, all problems are listed and the struct may be partially
+// populated. Values of variables whose name suggests a secret, e.g. because
+// it contains "PASSWORD" or "TOKEN", are masked by "*" in log messages.
+func Bind(target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return logError(fmt.Errorf(
+			"cannot bind to %T, a non-nil pointer to a struct is required", target,
+		))
+	}
+	v = v.Elem()
+	var problems []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		envName, ok := field.Tag.Lookup(envTag)
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if problem := bindField(v.Field(i), field, envName); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	if len(problems) > 0 {
+		return logError(fmt.Errorf(
+			"cannot bind %s: %s", v.Type(), strings.Join(problems, "; "),
+		))
+	}
+	return nil
+}
+
+// bindField sets the field from the environment variable envName. It returns
+// a description of the problem, or an empty string on success.
+func bindField(fieldValue reflect.Value, field reflect.StructField, envName string) string {
+	if !isSupportedField(fieldValue) {
+		return fmt.Sprintf("field %s has the unsupported type %s", field.Name, field.Type)
+	}
+	secret := isSecretName(envName)
+	val := getenv(envName)
+	if len(val) != 0 {
+		if err := setField(fieldValue, val); err != nil {
+			emitConfigEvent(envName, ConfigEventInvalid, nil)
+			if secret {
+				val = secretMasker(val)
+			}
+			return fmt.Sprintf("value '%s' for '%s' is not a valid %s", val, envName, field.Type)
+		}
+		if secret {
+			logSecret(envName, val)
+		} else {
+			logValue(envName, fieldValue.Interface())
+		}
+		return ""
+	}
+	if field.Tag.Get(requiredTag) == "true" {
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		return fmt.Sprintf("please set the environment variable '%s'", envName)
+	}
+	if defaultValue, ok := field.Tag.Lookup(defaultTag); ok {
+		if err := setField(fieldValue, defaultValue); err != nil {
+			return fmt.Sprintf(
+				"default value '%s' for '%s' is not a valid %s", defaultValue, envName, field.Type,
+			)
+		}
+	}
+	if secret {
+		logDefault(envName, maskSecret(fmt.Sprint(fieldValue.Interface())))
+	} else {
+		logDefault(envName, fieldValue.Interface())
+	}
+	return ""
+}
+
+// isSupportedField reports whether setField supports the type of fieldValue.
+func isSupportedField(fieldValue reflect.Value) bool {
+	switch fieldValue.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// setField parses val according to the type of fieldValue and sets it.
+func setField(fieldValue reflect.Value, val string) error {
+	if fieldValue.Type() == durationType {
+		dur, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(int64(dur))
+		return nil
+	}
+	switch fieldValue.Kind() {
+	case reflect.String:
+		fieldValue.SetString(val)
+	case reflect.Bool:
+		b, err := parseBool(val)
+		if err != nil {
+			return err
+		}
+		fieldValue.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, fieldValue.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, fieldValue.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fieldValue.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", fieldValue.Type())
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// nolint: goconst
+package envtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bindTestConfig struct {
+	Host     string        `env:"SOME_ARBITRARY_TEST_BIND_HOST" default:"localhost"`
+	Port     int           `env:"SOME_ARBITRARY_TEST_BIND_PORT" required:"true"`
+	Verbose  bool          `env:"SOME_ARBITRARY_TEST_BIND_VERBOSE"`
+	Ratio    float64       `env:"SOME_ARBITRARY_TEST_BIND_RATIO" default:"0.5"`
+	Timeout  time.Duration `env:"SOME_ARBITRARY_TEST_BIND_TIMEOUT" default:"30s"`
+	Password string        `env:"SOME_ARBITRARY_TEST_BIND_PASSWORD"`
+	Untagged string
+	internal string `env:"SOME_ARBITRARY_TEST_BIND_INTERNAL"`
+}
+
+func TestBind_PopulatesTaggedFields(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_HOST", "")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_PORT", "8080")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_VERBOSE", "yes")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_RATIO", "")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_TIMEOUT", "1m")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_PASSWORD", "s3cr3t")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_INTERNAL", "ignored")
+
+	config := bindTestConfig{Untagged: "kept"}
+	err := Bind(&config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, bindTestConfig{
+		Host:     "localhost",
+		Port:     8080,
+		Verbose:  true,
+		Ratio:    0.5,
+		Timeout:  time.Minute,
+		Password: "s3cr3t",
+		Untagged: "kept",
+	}, config)
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestBind_KeepsCurrentValueWithoutDefault(t *testing.T) {
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_PORT", "8080")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_VERBOSE", "")
+
+	config := bindTestConfig{Verbose: true}
+	err := Bind(&config)
+
+	assert.NoError(t, err)
+	assert.True(t, config.Verbose)
+}
+
+func TestBind_ListsAllProblems(t *testing.T) {
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_PORT", "")
+	t.Setenv("SOME_ARBITRARY_TEST_BIND_TIMEOUT", "soon")
+
+	err := Bind(&bindTestConfig{})
+
+	assert.EqualError(t, err, "cannot bind envtools.bindTestConfig: "+
+		"please set the environment variable 'SOME_ARBITRARY_TEST_BIND_PORT'; "+
+		"value 'soon' for 'SOME_ARBITRARY_TEST_BIND_TIMEOUT' is not a valid time.Duration")
+}
+
+func TestBind_FailsOnInvalidDefaultOrUnsupportedType(t *testing.T) {
+	t.Setenv(envVarName, "")
+	t.Setenv(otherVarName, "a,b")
+
+	err := Bind(&struct {
+		Count int      `env:"SOME_ARBITRARY_TEST_ENV_VAR_NAME" default:"many"`
+		Names []string `env:"SOME_OTHER_ARBITRARY_TEST_ENV_VAR_NAME"`
+	}{})
+
+	assert.ErrorContains(t, err,
+		"default value 'many' for '"+envVarName+"' is not a valid int; "+
+			"field Names has the unsupported type []string")
+}
+
+func TestBind_FailsIfNotPointerToStruct(t *testing.T) {
+	var config *bindTestConfig
+	number := 42
+	for _, target := range []interface{}{bindTestConfig{}, config, &number, nil} {
+		err := Bind(target)
+
+		assert.ErrorContains(t, err, "a non-nil pointer to a struct is required")
+	}
+}
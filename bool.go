@@ -0,0 +1,125 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	boolValueLevel     = LogLevelInfo
+	boolDefaultedLevel = LogLevelWarn
+)
+
+// SetBoolOrDefaultLogLevels sets the levels GetEnvBoolOrDefault logs at if the
+// variable is set and if it falls back to its default. By default, the former
+// is logged at info and the latter at warn level, as a feature flag running on
+// its default often means that the operator forgot to set it.
+func SetBoolOrDefaultLogLevels(valueLevel, defaultedLevel LogLevel) {
+	boolValueLevel = valueLevel
+	boolDefaultedLevel = defaultedLevel
+}
+
+// GetEnvBoolOrDefault looks up an environment variable and parses it as bool.
+// Besides the values accepted by strconv.ParseBool, like "1", "0", "true" and
+// "false", also "yes", "no", "on" and "off" are accepted, all of them
+// case-insensitively. If the variable is not set, the provided defaultValue
+// will be returned and a warning is logged, see SetBoolOrDefaultLogLevels.
+// If the value cannot be parsed, a warning is logged and the defaultValue
+// will be returned as well.
+func GetEnvBoolOrDefault(envName string, defaultValue bool) bool {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefaultAt(boolDefaultedLevel, envName, defaultValue)
+		return defaultValue
+	}
+	value, err := parseBool(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid boolean, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValueAt(boolValueLevel, envName, value)
+	return value
+}
+
+// GetEnvBoolOrFail looks up an environment variable and parses it as bool.
+// Parsing is strict: only "true", "false", "1" and "0" are accepted. If the
+// environment variable is not set or empty, or if the value is anything else,
+// an error is returned.
+func GetEnvBoolOrFail(envName string) (bool, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return false, err
+	}
+	var value bool
+	switch val {
+	case "true", "1":
+		value = true
+	case "false", "0":
+		value = false
+	default:
+		return false, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid boolean, expected one of "+
+				"'true', 'false', '1' or '0'",
+			val,
+			envName,
+		))
+	}
+	logValue(envName, value)
+
+	return value, nil
+}
+
+// GetEnvBoolWithCanonicalOrFail looks up an environment variable and parses it
+// as bool, accepting the same spellings as GetEnvBoolOrDefault. Besides the
+// parsed value, its canonical spelling "true" or "false" is returned, e.g. to
+// store the normalized form back. If the environment variable is not set or
+// empty, or if the value cannot be parsed, an error is returned.
+func GetEnvBoolWithCanonicalOrFail(envName string) (value bool, canonical string, err error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return false, "", err
+	}
+	value, err = parseBool(val)
+	if err != nil {
+		return false, "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid boolean", val, envName,
+		))
+	}
+	logValue(envName, value)
+
+	return value, strconv.FormatBool(value), nil
+}
+
+// parseBool parses val like strconv.ParseBool, but case-insensitively and
+// additionally accepting "yes", "no", "on" and "off".
+func parseBool(val string) (bool, error) {
+	switch lower := strings.ToLower(val); lower {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	default:
+		return strconv.ParseBool(lower)
+	}
+}
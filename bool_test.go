@@ -0,0 +1,148 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvBoolOrDefault_AcceptsFlexibleSpellings(t *testing.T) {
+	tests := map[string]bool{
+		"1": true, "true": true, "TRUE": true, "tRuE": true, "yes": true, "Yes": true,
+		"on": true, "ON": true, "t": true,
+		"0": false, "false": false, "False": false, "no": false, "NO": false,
+		"off": false, "Off": false, "f": false,
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvBoolOrDefault(envVarName, !expected)
+
+		assert.Equal(t, expected, actualValue, val)
+	}
+}
+
+func TestGetEnvBoolOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	actualValue := GetEnvBoolOrDefault(envVarName, true)
+
+	assert.True(t, actualValue)
+	assert.Contains(t, buf.String(), "level=warning msg=\"environment variable '"+envVarName+
+		"' is not set, defaulting to true\"")
+}
+
+func TestGetEnvBoolOrDefault_LogsAtConfiguredLevels(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.DebugLevel)
+	defer tearDownLogging()
+	SetBoolOrDefaultLogLevels(LogLevelDebug, LogLevelError)
+	defer SetBoolOrDefaultLogLevels(LogLevelInfo, LogLevelWarn)
+
+	t.Setenv(envVarName, "true")
+	GetEnvBoolOrDefault(envVarName, false)
+	t.Setenv(envVarName, "")
+	GetEnvBoolOrDefault(envVarName, false)
+
+	assert.Contains(t, buf.String(), "level=debug msg=\"using configured value 'true' for '"+
+		envVarName+"'\"")
+	assert.Contains(t, buf.String(), "level=error msg=\"environment variable '"+envVarName+
+		"' is set but empty, defaulting to false\"")
+}
+
+func TestGetEnvBoolOrDefault_WarnsAndReturnsDefaultIfMalformed(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "enabled")
+
+	actualValue := GetEnvBoolOrDefault(envVarName, false)
+
+	assert.False(t, actualValue)
+	assert.Contains(t, buf.String(), "level=warning msg=\"value 'enabled' for '"+envVarName+
+		"' is not a valid boolean, defaulting to false\"")
+}
+
+func TestGetEnvBoolOrFail_ParsesStrictly(t *testing.T) {
+	tests := map[string]bool{"true": true, "1": true, "false": false, "0": false}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvBoolOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, expected, actualValue, val)
+	}
+}
+
+func TestGetEnvBoolOrFail_FailsOnLenientValues(t *testing.T) {
+	for _, val := range []string{"yes", "TRUE", "on", "t"} {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvBoolOrFail(envVarName)
+
+		assert.ErrorContains(t, err,
+			"value '"+val+"' for '"+envVarName+"' is not a valid boolean", val)
+	}
+}
+
+func TestGetEnvBoolOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvBoolOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvBoolWithCanonicalOrFail_ReturnsCanonicalSpelling(t *testing.T) {
+	tests := map[string]bool{"YES": true, "On": true, "1": true, "no": false, "FALSE": false}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		actualValue, canonical, err := GetEnvBoolWithCanonicalOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, expected, actualValue, val)
+		assert.Equal(t, strconv.FormatBool(expected), canonical, val)
+	}
+}
+
+func TestGetEnvBoolWithCanonicalOrFail_FailsIfMalformed(t *testing.T) {
+	t.Setenv(envVarName, "maybe")
+
+	_, canonical, err := GetEnvBoolWithCanonicalOrFail(envVarName)
+
+	assert.EqualError(t, err, "value 'maybe' for '"+envVarName+"' is not a valid boolean")
+	assert.Empty(t, canonical)
+}
+
+func TestGetEnvBoolWithCanonicalOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, _, err := GetEnvBoolWithCanonicalOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+const opaqueAlpha = 255
+
+// GetEnvColorOrFail looks up an environment variable and parses it as a hex
+// color in one of the forms "#RGB", "#RRGGBB" or "#RRGGBBAA". If no alpha
+// channel is given, alpha defaults to 255. An error is returned if the
+// variable is not set or empty or if the value is malformed.
+func GetEnvColorOrFail(envName string) (r, g, b, a uint8, err error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	c, err := parseHexColor(val)
+	if err != nil {
+		return 0, 0, 0, 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid color: %w", val, envName, err,
+		))
+	}
+	logValue(envName, val)
+
+	return c.R, c.G, c.B, c.A, nil
+}
+
+// GetEnvColorOrDefault looks up an environment variable and parses it as a hex
+// color like GetEnvColorOrFail does. If the variable is not set or the value
+// is malformed, the provided defaultValue will be returned.
+func GetEnvColorOrDefault(envName string, defaultValue color.RGBA) color.RGBA {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	c, err := parseHexColor(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid color (%v), defaulting to %v",
+			val,
+			envName,
+			err,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, val)
+	return c
+}
+
+// parseHexColor parses "#RGB", "#RRGGBB" and "#RRGGBBAA" hex colors.
+func parseHexColor(val string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(val, "#")
+	if len(hex) == len(val) {
+		return color.RGBA{}, fmt.Errorf("missing leading '#'")
+	}
+	if len(hex) == len("RGB") {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == len("RRGGBB") {
+		hex += "ff"
+	}
+	if len(hex) != len("RRGGBBAA") {
+		return color.RGBA{}, fmt.Errorf("expected #RGB, #RRGGBB or #RRGGBBAA")
+	}
+	rgba, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex digits")
+	}
+	return color.RGBA{
+		R: uint8(rgba >> 24),
+		G: uint8(rgba >> 16),
+		B: uint8(rgba >> 8),
+		A: uint8(rgba),
+	}, nil
+}
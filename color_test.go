@@ -0,0 +1,76 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvColorOrFail_ParsesSupportedForms(t *testing.T) {
+	tests := map[string][4]uint8{
+		"#1a2b3c":   {0x1a, 0x2b, 0x3c, 255},
+		"#1A2B3C80": {0x1a, 0x2b, 0x3c, 0x80},
+		"#abc":      {0xaa, 0xbb, 0xcc, 255},
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		r, g, b, a, err := GetEnvColorOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, expected, [4]uint8{r, g, b, a}, val)
+	}
+}
+
+func TestGetEnvColorOrFail_FailsOnMalformedInput(t *testing.T) {
+	for _, val := range []string{"1a2b3c", "#1a2b", "#gggggg", "#+1a2b3c"} {
+		t.Setenv(envVarName, val)
+
+		_, _, _, _, err := GetEnvColorOrFail(envVarName)
+
+		assert.ErrorContains(t, err, "value '"+val+"' for '"+envVarName+"' is not a valid color")
+	}
+}
+
+func TestGetEnvColorOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, _, _, _, err := GetEnvColorOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvColorOrDefault_SucceedsIfSet(t *testing.T) {
+	t.Setenv(envVarName, "#102030")
+
+	actualValue := GetEnvColorOrDefault(envVarName, color.RGBA{})
+
+	assert.Equal(t, color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 255}, actualValue)
+}
+
+func TestGetEnvColorOrDefault_ReturnsDefaultIfMalformedOrNotSet(t *testing.T) {
+	defaultValue := color.RGBA{R: 1, G: 2, B: 3, A: 4}
+	for _, val := range []string{"", "red"} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvColorOrDefault(envVarName, defaultValue)
+
+		assert.Equal(t, defaultValue, actualValue, val)
+	}
+}
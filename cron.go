@@ -0,0 +1,175 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField describes a field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC",
+	}},
+	// Both 0 and 7 denote Sunday.
+	{name: "day of week", min: 0, max: 7, names: []string{
+		"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT",
+	}},
+}
+
+var cronDescriptors = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// GetEnvCronOrFail looks up an environment variable holding a cron expression
+// with the five fields minute, hour, day of month, month and day of week,
+// e.g. "0 9 * * MON-FRI", or a descriptor like "@daily". Fields may contain
+// lists, ranges and steps, months and days of week also their three letter
+// English names. If the environment variable is not set or empty, or if the
+// expression is malformed, an error naming the offending field is returned.
+func GetEnvCronOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if problem := checkCronExpression(val); problem != "" {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid cron expression: %s", val, envName, problem,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// GetEnvCronWithTZOrFail looks up an environment variable holding a cron
+// expression like GetEnvCronOrFail does, optionally prefixed by a timezone as
+// accepted by many schedulers, e.g. "TZ=America/New_York 0 9 * * *". The
+// prefixes "TZ=" and "CRON_TZ=" are supported. The expression is returned
+// without prefix and the timezone separately, loaded by time.LoadLocation.
+// Without prefix, the timezone is time.Local. If the environment variable is
+// not set or empty, if the timezone is unknown or if the expression is
+// malformed, an error is returned.
+func GetEnvCronWithTZOrFail(envName string) (expr string, loc *time.Location, err error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", nil, err
+	}
+	expr, loc = strings.TrimSpace(val), time.Local
+	for _, prefix := range []string{"TZ=", "CRON_TZ="} {
+		if !strings.HasPrefix(expr, prefix) {
+			continue
+		}
+		tz, rest, _ := strings.Cut(strings.TrimPrefix(expr, prefix), " ")
+		loc, err = time.LoadLocation(tz)
+		if err != nil || len(tz) == 0 {
+			return "", nil, logEnvError(envName, fmt.Errorf(
+				"value '%s' for '%s' has an invalid timezone '%s'", val, envName, tz,
+			))
+		}
+		expr = strings.TrimSpace(rest)
+		break
+	}
+	if problem := checkCronExpression(expr); problem != "" {
+		return "", nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid cron expression: %s", val, envName, problem,
+		))
+	}
+	logValue(envName, val)
+
+	return expr, loc, nil
+}
+
+// checkCronExpression returns a description of the first problem of the cron
+// expression, or an empty string if it is valid.
+func checkCronExpression(expr string) string {
+	if strings.HasPrefix(expr, "@") {
+		if !cronDescriptors[expr] {
+			return fmt.Sprintf("unknown descriptor '%s'", expr)
+		}
+		return ""
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Sprintf("expected %d fields, got %d", len(cronFields), len(fields))
+	}
+	for i, field := range fields {
+		if problem := cronFields[i].check(field); problem != "" {
+			return fmt.Sprintf("%s field '%s' %s", cronFields[i].name, field, problem)
+		}
+	}
+	return ""
+}
+
+// check returns a description of the first problem of the value of the field,
+// or an empty string if it is valid.
+func (f cronField) check(val string) string {
+	for _, part := range strings.Split(val, ",") {
+		rangePart, step, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 {
+				return fmt.Sprintf("has an invalid step '%s'", step)
+			}
+		}
+		if rangePart == "*" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rangePart, "-")
+		loValue, ok := f.parse(lo)
+		if !ok {
+			return fmt.Sprintf("has an invalid value '%s', expected %d-%d", lo, f.min, f.max)
+		}
+		if !isRange {
+			continue
+		}
+		hiValue, ok := f.parse(hi)
+		if !ok {
+			return fmt.Sprintf("has an invalid value '%s', expected %d-%d", hi, f.min, f.max)
+		}
+		if loValue > hiValue {
+			return fmt.Sprintf("has an inverted range '%s'", rangePart)
+		}
+	}
+	return ""
+}
+
+// parse parses a single value of the field, either a number or a name.
+func (f cronField) parse(val string) (int, bool) {
+	for i, name := range f.names {
+		if strings.EqualFold(val, name) {
+			return f.min + i, true
+		}
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < f.min || n > f.max {
+		return 0, false
+	}
+	return n, true
+}
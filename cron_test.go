@@ -0,0 +1,98 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// nolint: goconst
+package envtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvCronOrFail_AcceptsValidExpressions(t *testing.T) {
+	for _, val := range []string{
+		"0 9 * * *", "*/15 0-6,22-23 1,15 jan-JUN MON-FRI", "0 0 * * 7", "@daily",
+	} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvCronOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, val, actualValue)
+	}
+}
+
+func TestGetEnvCronOrFail_NamesOffendingField(t *testing.T) {
+	tests := map[string]string{
+		"0 9 * *":      "expected 5 fields, got 4",
+		"60 9 * * *":   "minute field '60' has an invalid value '60', expected 0-59",
+		"0 9 0 * *":    "day of month field '0' has an invalid value '0', expected 1-31",
+		"0 9 * * 5-1":  "day of week field '5-1' has an inverted range '5-1'",
+		"0 */0 * * *":  "hour field '*/0' has an invalid step '0'",
+		"0 9 * FOO *":  "month field 'FOO' has an invalid value 'FOO', expected 1-12",
+		"@fortnightly": "unknown descriptor '@fortnightly'",
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvCronOrFail(envVarName)
+
+		assert.EqualError(t, err,
+			"value '"+val+"' for '"+envVarName+"' is not a valid cron expression: "+expected)
+	}
+}
+
+func TestGetEnvCronOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvCronOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvCronWithTZOrFail_ReturnsTimezoneSeparately(t *testing.T) {
+	t.Setenv(envVarName, "TZ=UTC 0 9 * * *")
+
+	expr, loc, err := GetEnvCronWithTZOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0 9 * * *", expr)
+	assert.Equal(t, time.UTC, loc)
+}
+
+func TestGetEnvCronWithTZOrFail_DefaultsToLocalTimezone(t *testing.T) {
+	t.Setenv(envVarName, "@hourly")
+
+	expr, loc, err := GetEnvCronWithTZOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "@hourly", expr)
+	assert.Equal(t, time.Local, loc)
+}
+
+func TestGetEnvCronWithTZOrFail_FailsDistinctly(t *testing.T) {
+	t.Setenv(envVarName, "CRON_TZ=Mars/Olympus_Mons 0 9 * * *")
+	_, _, err := GetEnvCronWithTZOrFail(envVarName)
+	assert.EqualError(t, err, "value 'CRON_TZ=Mars/Olympus_Mons 0 9 * * *' for '"+envVarName+
+		"' has an invalid timezone 'Mars/Olympus_Mons'")
+
+	t.Setenv(envVarName, "TZ=UTC 0 25 * * *")
+	_, _, err = GetEnvCronWithTZOrFail(envVarName)
+	assert.EqualError(t, err, "value 'TZ=UTC 0 25 * * *' for '"+envVarName+
+		"' is not a valid cron expression: hour field '25' has an invalid value '25', "+
+		"expected 0-23")
+}
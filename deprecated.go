@@ -0,0 +1,193 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var strictMode = false
+
+var (
+	deprecatedMutex sync.RWMutex
+	// deprecatedAliases maps new names to their former names in the order of
+	// registration.
+	deprecatedAliases = map[string][]string{}
+	// deprecatedReplacements maps former names to their new names.
+	deprecatedReplacements = map[string]string{}
+)
+
+// SetStrictMode enables or disables strict mode, which is disabled by default.
+// In strict mode, reading a value that is only set under a deprecated name is
+// an error instead of a warning, see GetEnvWithDeprecated. This allows to
+// enforce the migration to new names at a chosen point, e.g. before a release.
+func SetStrictMode(strict bool) {
+	strictMode = strict
+}
+
+// RegisterDeprecated registers oldName as deprecated alias of newName for all
+// getters of this package. If newName is looked up but not set, the value of
+// oldName is used instead and a warning asks to use newName. If both are set,
+// newName takes precedence. Looking up oldName directly logs the warning as
+// well. In strict mode, see SetStrictMode, the value of oldName is ignored
+// and an error is logged instead, so it is treated as if it was not set.
+// RegisterDeprecated is safe for concurrent use, e.g. from init functions of
+// several packages.
+func RegisterDeprecated(oldName, newName string) {
+	deprecatedMutex.Lock()
+	defer deprecatedMutex.Unlock()
+	if deprecatedReplacements[oldName] == newName {
+		return
+	}
+	deprecatedReplacements[oldName] = newName
+	deprecatedAliases[newName] = append(deprecatedAliases[newName], oldName)
+}
+
+// getenv is like os.Getenv, but honours the aliases registered with
+// RegisterDeprecated.
+func getenv(envName string) string {
+	val, _ := lookupEnv(envName)
+	return val
+}
+
+// lookupEnv is like os.LookupEnv, but honours the aliases registered with
+// RegisterDeprecated.
+func lookupEnv(envName string) (string, bool) {
+	return resolveEnv(envName, true)
+}
+
+// peekEnv is like lookupEnv, but does not log the use of deprecated names,
+// e.g. to inspect a variable before or after reading it through a getter.
+func peekEnv(envName string) (string, bool) {
+	return resolveEnv(envName, false)
+}
+
+// resolveEnv looks up the environment variable envName, falling back to its
+// registered aliases. If report is set, the use of deprecated names is logged.
+func resolveEnv(envName string, report bool) (string, bool) {
+	deprecatedMutex.RLock()
+	newName, deprecated := deprecatedReplacements[envName]
+	aliases := deprecatedAliases[envName]
+	deprecatedMutex.RUnlock()
+
+	val, found := os.LookupEnv(envName)
+	if len(val) != 0 {
+		if deprecated && report {
+			warnDeprecated(envName, envName, newName)
+		}
+		return val, found
+	}
+	for _, alias := range aliases {
+		if aliasVal := os.Getenv(alias); len(aliasVal) != 0 {
+			if strictMode {
+				if report {
+					logger.withEnv(envName).Errorln(fmt.Sprintf(
+						"environment variable '%s' is deprecated, use '%s', ignoring its value",
+						alias,
+						envName,
+					))
+				}
+				continue
+			}
+			if report {
+				warnDeprecated(envName, alias, envName)
+			}
+			return aliasVal, true
+		}
+	}
+	return val, found
+}
+
+// warnDeprecated logs that the registered oldName is read instead of newName.
+func warnDeprecated(envName, oldName, newName string) {
+	logger.withEnv(envName).Warnf(
+		"environment variable '%v' is deprecated, use '%v'", oldName, newName,
+	)
+}
+
+// GetEnvWithDeprecated looks up the environment variable envName first. If it
+// is not set, the environment variable deprecatedName, a former name of the
+// same setting, is looked up instead and a warning asks to rename it. In
+// strict mode, see SetStrictMode, an error is returned instead. If neither is
+// set, the provided defaultValue will be returned.
+func GetEnvWithDeprecated(envName, deprecatedName, defaultValue string) (string, error) {
+	if val := getenv(envName); len(val) != 0 {
+		logValue(envName, val)
+		return val, nil
+	}
+	val := getenv(deprecatedName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue, nil
+	}
+	if strictMode {
+		return "", logEnvError(envName, fmt.Errorf(
+			"environment variable '%s' is deprecated, please rename it to '%s'",
+			deprecatedName,
+			envName,
+		))
+	}
+	logger.withEnv(envName).Warnf(
+		"environment variable '%v' is deprecated, please rename it to '%v'",
+		deprecatedName,
+		envName,
+	)
+	logValue(deprecatedName, val)
+	return val, nil
+}
+
+// GetEnvFirstSetOrFail looks up the provided environment variables in order
+// and returns the value of the first one that is set and not empty, together
+// with its name. This allows to rename a variable without downtime: the
+// preferred name comes first, followed by its former names. If a former name
+// supplies the value, a warning asks to rename it to the preferred one. In
+// strict mode, see SetStrictMode, an error is returned instead. An error is
+// returned as well if none of the variables is set.
+func GetEnvFirstSetOrFail(names ...string) (value string, usedName string, err error) {
+	if len(names) == 0 {
+		return "", "", logError(fmt.Errorf("no environment variable names given"))
+	}
+	preferred := names[0]
+	for i, name := range names {
+		val := getenv(name)
+		if len(val) == 0 {
+			continue
+		}
+		if i > 0 {
+			if strictMode {
+				return "", "", logEnvError(preferred, fmt.Errorf(
+					"environment variable '%s' is deprecated, please rename it to '%s'",
+					name,
+					preferred,
+				))
+			}
+			logger.withEnv(preferred).Warnf(
+				"environment variable '%v' is deprecated, please rename it to '%v'",
+				name,
+				preferred,
+			)
+		}
+		logValue(name, val)
+		return val, name, nil
+	}
+	return "", "", logEnvError(preferred, fmt.Errorf(
+		"please set one of the environment variables '%s'",
+		strings.Join(names, "', '"),
+	))
+}
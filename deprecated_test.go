@@ -0,0 +1,255 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const deprecatedVarName = "SOME_ARBITRARY_TEST_DEPRECATED_VAR_NAME"
+
+func TestGetEnvWithDeprecated_PrefersNewName(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	actualValue, err := GetEnvWithDeprecated(envVarName, deprecatedVarName, "Default Value")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvWithDeprecated_WarnsAndUsesDeprecatedName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	actualValue, err := GetEnvWithDeprecated(envVarName, deprecatedVarName, "Default Value")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Old Value", actualValue)
+	assert.Contains(t, buf.String(), "environment variable '"+deprecatedVarName+
+		"' is deprecated, please rename it to '"+envVarName+"'")
+}
+
+func TestGetEnvWithDeprecated_FailsOnDeprecatedNameInStrictMode(t *testing.T) {
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	_, err := GetEnvWithDeprecated(envVarName, deprecatedVarName, "Default Value")
+
+	assert.EqualError(t, err, "environment variable '"+deprecatedVarName+
+		"' is deprecated, please rename it to '"+envVarName+"'")
+}
+
+func TestGetEnvWithDeprecated_ReturnsDefaultIfNeitherSet(t *testing.T) {
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "")
+
+	actualValue, err := GetEnvWithDeprecated(envVarName, deprecatedVarName, "Default Value")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Default Value", actualValue)
+}
+
+func TestGetEnvFirstSetOrFail_ReturnsFirstSetVariable(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	value, usedName, err := GetEnvFirstSetOrFail(envVarName, deprecatedVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedValue, value)
+	assert.Equal(t, envVarName, usedName)
+}
+
+func TestGetEnvFirstSetOrFail_WarnsIfFormerNameIsUsed(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	t.Setenv(otherVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	value, usedName, err := GetEnvFirstSetOrFail(envVarName, otherVarName, deprecatedVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Old Value", value)
+	assert.Equal(t, deprecatedVarName, usedName)
+	assert.Contains(t, buf.String(), "environment variable '"+deprecatedVarName+
+		"' is deprecated, please rename it to '"+envVarName+"'")
+}
+
+func TestGetEnvFirstSetOrFail_FailsOnFormerNameInStrictMode(t *testing.T) {
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	_, _, err := GetEnvFirstSetOrFail(envVarName, deprecatedVarName)
+
+	assert.EqualError(t, err, "environment variable '"+deprecatedVarName+
+		"' is deprecated, please rename it to '"+envVarName+"'")
+}
+
+func TestGetEnvFirstSetOrFail_FailsIfNoneSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "")
+
+	_, _, err := GetEnvFirstSetOrFail(envVarName, deprecatedVarName)
+	assert.EqualError(t, err, "please set one of the environment variables '"+
+		envVarName+"', '"+deprecatedVarName+"'")
+
+	_, _, err = GetEnvFirstSetOrFail()
+	assert.EqualError(t, err, "no environment variable names given")
+}
+
+// registerDeprecatedAndTearDown registers oldName as deprecated alias of
+// newName and removes the registration when the test finishes.
+func registerDeprecatedAndTearDown(t *testing.T, oldName, newName string) {
+	RegisterDeprecated(oldName, newName)
+	t.Cleanup(func() {
+		deprecatedMutex.Lock()
+		defer deprecatedMutex.Unlock()
+		delete(deprecatedReplacements, oldName)
+		delete(deprecatedAliases, newName)
+	})
+}
+
+func TestRegisterDeprecated_GettersFallBackToOldName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+	registerDeprecatedAndTearDown(t, deprecatedVarName, envVarName)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "42")
+
+	assert.Equal(t, "42", GetEnvOrDefault(envVarName, "Default Value"))
+	actualValue, err := GetEnvIntOrFail(envVarName)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, actualValue)
+	assert.Contains(t, buf.String(), "environment variable '"+deprecatedVarName+
+		"' is deprecated, use '"+envVarName+"'")
+}
+
+func TestRegisterDeprecated_PrefersNewName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+	registerDeprecatedAndTearDown(t, deprecatedVarName, envVarName)
+
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	assert.Equal(t, expectedValue, GetEnvOrDefault(envVarName, "Default Value"))
+	assert.Empty(t, buf.String())
+}
+
+func TestRegisterDeprecated_WarnsIfOldNameIsReadDirectly(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+	registerDeprecatedAndTearDown(t, deprecatedVarName, envVarName)
+
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	assert.Equal(t, "Old Value", GetEnvOrDefault(deprecatedVarName, "Default Value"))
+	assert.Contains(t, buf.String(), "environment variable '"+deprecatedVarName+
+		"' is deprecated, use '"+envVarName+"'")
+}
+
+func TestRegisterDeprecated_IgnoresOldNameInStrictMode(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+	registerDeprecatedAndTearDown(t, deprecatedVarName, envVarName)
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	_, err := GetEnvOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.Contains(t, buf.String(), "level=error msg=\"environment variable '"+
+		deprecatedVarName+"' is deprecated, use '"+envVarName+"', ignoring its value\"")
+}
+
+func TestRegisterDeprecated_IsSafeForConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			registerDeprecatedAndTearDown(t, fmt.Sprintf("%s_%d", deprecatedVarName, i), envVarName)
+			_ = getenv(envVarName)
+		}(i)
+	}
+	wg.Wait()
+
+	deprecatedMutex.RLock()
+	defer deprecatedMutex.RUnlock()
+	assert.Len(t, deprecatedAliases[envVarName], 10)
+}
+
+func TestRegisterDeprecated_IsHonouredByAuditAndSources(t *testing.T) {
+	registerDeprecatedAndTearDown(t, deprecatedVarName, envVarName)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	audit := NewAuditGetter()
+	audit.GetEnvOrDefault(envVarName, "Default Value")
+	entries := audit.AuditLog()
+	assert.Len(t, entries, 1)
+	assert.True(t, entries[0].Found)
+	assert.Equal(t, AuditSourceEnvironment, entries[0].Source)
+	assert.Equal(t, "Old Value", entries[0].Value)
+
+	val, found := EnvSource().Lookup(envVarName)
+	assert.True(t, found)
+	assert.Equal(t, "Old Value", val)
+}
+
+func TestRegisterDeprecated_IgnoredOldNameIsReportedAsSetInStrictMode(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	registerDeprecatedAndTearDown(t, deprecatedVarName, envVarName)
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+
+	t.Setenv(envVarName, "")
+	t.Setenv(deprecatedVarName, "Old Value")
+
+	GetEnvOrDefault(envVarName, "Default Value")
+
+	assert.Contains(t, buf.String(), "environment variable '"+envVarName+
+		"' is set but empty, defaulting to Default Value")
+	assert.Equal(t, 1, strings.Count(buf.String(), "ignoring its value"))
+}
@@ -0,0 +1,308 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetEnvDurationOrDefault looks up an environment variable and parses it as
+// duration as understood by time.ParseDuration, e.g. "30s" or "5m". If the
+// variable is not set, the provided defaultValue will be returned. If the
+// value cannot be parsed, a warning is logged and the defaultValue will be
+// returned as well.
+func GetEnvDurationOrDefault(envName string, defaultValue time.Duration) time.Duration {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid duration, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, dur)
+	return dur
+}
+
+// GetEnvDurationOrFail looks up an environment variable and parses it as
+// duration as understood by time.ParseDuration, e.g. "30s" or "5m". If the
+// environment variable is not set or empty, or if the value cannot be parsed,
+// an error is returned.
+func GetEnvDurationOrFail(envName string) (time.Duration, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	dur, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid duration: %w", val, envName, err,
+		))
+	}
+	logValue(envName, dur)
+
+	return dur, nil
+}
+
+// GetEnvDurationSaneOrFail looks up an environment variable and parses it as
+// duration like GetEnvDurationOrFail does. Additionally, a warning is logged
+// if the duration is below warnBelow or above warnAbove, as such values are
+// likely caused by a wrong unit, e.g. "30ns" or "30h" instead of "30s". The
+// value is returned nevertheless. A bound of zero disables the respective
+// check.
+func GetEnvDurationSaneOrFail(
+	envName string,
+	warnBelow, warnAbove time.Duration,
+) (time.Duration, error) {
+	dur, err := GetEnvDurationOrFail(envName)
+	if err != nil {
+		return 0, err
+	}
+	if warnBelow > 0 && dur < warnBelow {
+		logger.withEnv(envName).withValue(dur).Warnf(
+			"value '%v' for '%v' is suspiciously small, it is below %v, "+
+				"please check the unit",
+			dur,
+			envName,
+			warnBelow,
+		)
+	}
+	if warnAbove > 0 && dur > warnAbove {
+		logger.withEnv(envName).withValue(dur).Warnf(
+			"value '%v' for '%v' is suspiciously large, it is above %v, "+
+				"please check the unit",
+			dur,
+			envName,
+			warnAbove,
+		)
+	}
+
+	return dur, nil
+}
+
+// GetEnvBackoffScheduleOrFail looks up an environment variable holding a list
+// of retry delays like "100ms,500ms,2s" separated by sep. Every element is
+// trimmed and parsed with time.ParseDuration, empty elements are dropped.
+// An error is returned if the variable is not set or empty, if it contains no
+// delay, if a delay cannot be parsed or if the delays are not non-decreasing.
+func GetEnvBackoffScheduleOrFail(envName, sep string) ([]time.Duration, error) {
+	if len(sep) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf("empty separator for '%s'", envName))
+	}
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	elements := splitTrimmed(val, sep)
+	if len(elements) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"at least one delay required in '%s'", envName,
+		))
+	}
+	delays := make([]time.Duration, 0, len(elements))
+	for i, element := range elements {
+		delay, err := time.ParseDuration(element)
+		if err != nil {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"element %d '%s' of '%s' is not a valid duration: %w", i, element, envName, err,
+			))
+		}
+		if i > 0 && delay < delays[i-1] {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"delays of '%s' are not non-decreasing: element %d '%v' is "+
+					"smaller than element %d '%v'",
+				envName,
+				i,
+				delay,
+				i-1,
+				delays[i-1],
+			))
+		}
+		delays = append(delays, delay)
+	}
+	logValue(envName, delays)
+
+	return delays, nil
+}
+
+// GetEnvNamedDurationsOrFail looks up an environment variable holding named
+// durations like "mon=9h,fri=17h", where pairs are separated by pairSep and
+// name and duration by kvSep. Names and durations are trimmed, empty pairs
+// are dropped and durations are parsed with time.ParseDuration.
+// An error naming the position of the offending pair is returned if a pair
+// has no kvSep, an empty or duplicate name or an invalid duration. An error
+// is returned as well if the variable is not set or empty.
+func GetEnvNamedDurationsOrFail(
+	envName, pairSep, kvSep string,
+) (map[string]time.Duration, error) {
+	pairs, err := requirePairs(envName, pairSep, kvSep)
+	if err != nil {
+		return nil, err
+	}
+	durations := make(map[string]time.Duration, len(pairs))
+	for i, pair := range pairs {
+		duration, err := time.ParseDuration(pair.value)
+		if err != nil {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"pair %d '%s' of '%s' has an invalid duration: %w", i, pair.raw, envName, err,
+			))
+		}
+		durations[pair.key] = duration
+	}
+	logValue(envName, durations)
+
+	return durations, nil
+}
+
+// GetEnvCountOrDurationOrFail looks up an environment variable that accepts
+// either a count like "100" or a duration like "30s", as found in rate limiter
+// configuration. Parsing as duration with time.ParseDuration is tried first,
+// parsing as base-10 integer second; isDuration reports which one applied.
+// Note that "0" is a valid duration and is therefore reported as such.
+// An error is returned if the variable is not set or empty or if the value is
+// neither a duration nor a count.
+func GetEnvCountOrDurationOrFail(
+	envName string,
+) (count int, dur time.Duration, isDuration bool, err error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if dur, err := time.ParseDuration(val); err == nil {
+		logValue(envName, dur)
+		return 0, dur, true, nil
+	}
+	count, err = strconv.Atoi(val)
+	if err != nil {
+		return 0, 0, false, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is neither a valid duration nor a valid count",
+			val,
+			envName,
+		))
+	}
+	logValue(envName, count)
+
+	return count, 0, false, nil
+}
+
+var isoDurationPattern = regexp.MustCompile(
+	`^(-)?P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`,
+)
+
+// GetEnvISODurationOrFail looks up an environment variable and parses it as an
+// ISO 8601 duration like "PT1H30M" or "P1DT12H", as emitted by e.g. the JVM
+// or .NET. Days, hours, minutes and (fractional) seconds are supported, a day
+// being 24 hours. Years, months and weeks are not supported as they have no
+// fixed length. An error is returned if the variable is not set or empty or
+// if the value is malformed.
+func GetEnvISODurationOrFail(envName string) (time.Duration, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	dur, err := parseISODuration(val)
+	if err != nil {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid ISO 8601 duration: %w", val, envName, err,
+		))
+	}
+	logValue(envName, dur)
+
+	return dur, nil
+}
+
+// GetEnvISODurationOrDefault looks up an environment variable and parses it as
+// an ISO 8601 duration like GetEnvISODurationOrFail does. If the variable is
+// not set or the value is malformed, the provided defaultValue will be
+// returned.
+func GetEnvISODurationOrDefault(envName string, defaultValue time.Duration) time.Duration {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	dur, err := parseISODuration(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid ISO 8601 duration (%v), defaulting to %v",
+			val,
+			envName,
+			err,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, dur)
+	return dur
+}
+
+// parseISODuration parses the days, hours, minutes and seconds of an ISO 8601
+// duration.
+func parseISODuration(val string) (time.Duration, error) {
+	match := isoDurationPattern.FindStringSubmatch(val)
+	if match == nil || strings.HasSuffix(val, "T") || len(strings.Trim(val, "-P")) == 0 {
+		return 0, fmt.Errorf("expected a format like 'P1DT2H3M4.5S'")
+	}
+	units := []time.Duration{24 * time.Hour, time.Hour, time.Minute, time.Second}
+	var dur time.Duration
+	for i, unit := range units {
+		component := strings.Replace(match[i+2], ",", ".", 1)
+		if component == "" {
+			continue
+		}
+		// Integer arithmetic is used throughout, as float64 cannot represent
+		// durations close to the maximum precisely.
+		whole, fraction, _ := strings.Cut(component, ".")
+		amount, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || amount > math.MaxInt64/int64(unit) {
+			return 0, fmt.Errorf("duration out of range")
+		}
+		part := time.Duration(amount) * unit
+		if fraction != "" {
+			// Only seconds may be fractional, digits beyond nanoseconds are
+			// truncated.
+			fraction = (fraction + "000000000")[:9]
+			nanos, err := strconv.ParseInt(fraction, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			if part > math.MaxInt64-time.Duration(nanos) {
+				return 0, fmt.Errorf("duration out of range")
+			}
+			part += time.Duration(nanos)
+		}
+		if dur > math.MaxInt64-part {
+			return 0, fmt.Errorf("duration out of range")
+		}
+		dur += part
+	}
+	if match[1] == "-" {
+		dur = -dur
+	}
+	return dur, nil
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvDurationOrDefault_ParsesDuration(t *testing.T) {
+	t.Setenv(envVarName, "1m30s")
+
+	actualValue := GetEnvDurationOrDefault(envVarName, time.Second)
+
+	assert.Equal(t, 90*time.Second, actualValue)
+}
+
+func TestGetEnvDurationOrDefault_ReturnsDefaultIfNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvDurationOrDefault(envVarName, time.Second)
+
+	assert.Equal(t, time.Second, actualValue)
+}
+
+func TestGetEnvDurationOrDefault_WarnsAndReturnsDefaultIfInvalid(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "30")
+
+	actualValue := GetEnvDurationOrDefault(envVarName, time.Second)
+
+	assert.Equal(t, time.Second, actualValue)
+	assert.Contains(t, buf.String(),
+		"value '30' for '"+envVarName+"' is not a valid duration, defaulting to 1s")
+}
+
+func TestGetEnvDurationOrFail_ParsesDuration(t *testing.T) {
+	t.Setenv(envVarName, "5m")
+
+	actualValue, err := GetEnvDurationOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, actualValue)
+}
+
+func TestGetEnvDurationOrFail_FailsOnInvalidValue(t *testing.T) {
+	t.Setenv(envVarName, "5 minutes")
+
+	_, err := GetEnvDurationOrFail(envVarName)
+
+	assert.ErrorContains(t, err,
+		"value '5 minutes' for '"+envVarName+"' is not a valid duration")
+}
+
+func TestGetEnvDurationOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvDurationOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvDurationSaneOrFail_WarnsButReturnsSuspiciousValue(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "30ns")
+	actualValue, err := GetEnvDurationSaneOrFail(envVarName, time.Second, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Nanosecond, actualValue)
+	assert.Contains(t, buf.String(),
+		"value '30ns' for '"+envVarName+"' is suspiciously small, it is below 1s")
+
+	t.Setenv(envVarName, "30h")
+	actualValue, err = GetEnvDurationSaneOrFail(envVarName, time.Second, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Hour, actualValue)
+	assert.Contains(t, buf.String(),
+		"value '30h0m0s' for '"+envVarName+"' is suspiciously large, it is above 1h0m0s")
+}
+
+func TestGetEnvDurationSaneOrFail_DoesNotWarnWithinBoundsOrIfDisabled(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "30s")
+	_, err := GetEnvDurationSaneOrFail(envVarName, time.Second, time.Hour)
+	assert.NoError(t, err)
+	_, err = GetEnvDurationSaneOrFail(envVarName, 0, 0)
+	assert.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "suspiciously")
+}
+
+func TestGetEnvDurationSaneOrFail_FailsOnInvalidValue(t *testing.T) {
+	t.Setenv(envVarName, "30")
+
+	_, err := GetEnvDurationSaneOrFail(envVarName, time.Second, time.Hour)
+
+	assert.ErrorContains(t, err, "value '30' for '"+envVarName+"' is not a valid duration")
+}
+
+func TestGetEnvBackoffScheduleOrFail_SucceedsIfNonDecreasing(t *testing.T) {
+	t.Setenv(envVarName, "100ms, 500ms,500ms, 2s")
+
+	actualValue, err := GetEnvBackoffScheduleOrFail(envVarName, ",")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond, 2 * time.Second,
+	}, actualValue)
+}
+
+func TestGetEnvBackoffScheduleOrFail_FailsOnFirstOutOfOrderPair(t *testing.T) {
+	t.Setenv(envVarName, "1s,2s,1s,500ms")
+
+	_, err := GetEnvBackoffScheduleOrFail(envVarName, ",")
+
+	assert.EqualError(t, err, "delays of '"+envVarName+"' are not non-decreasing: "+
+		"element 2 '1s' is smaller than element 1 '2s'")
+}
+
+func TestGetEnvBackoffScheduleOrFail_FailsOnInvalidDuration(t *testing.T) {
+	t.Setenv(envVarName, "1s;soon")
+
+	_, err := GetEnvBackoffScheduleOrFail(envVarName, ";")
+
+	assert.ErrorContains(t, err, "element 1 'soon' of '"+envVarName+"' is not a valid duration")
+}
+
+func TestGetEnvBackoffScheduleOrFail_FailsIfNoDelay(t *testing.T) {
+	t.Setenv(envVarName, " , ")
+
+	_, err := GetEnvBackoffScheduleOrFail(envVarName, ",")
+
+	assert.ErrorContains(t, err, "at least one delay required in '"+envVarName+"'")
+}
+
+func TestGetEnvBackoffScheduleOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvBackoffScheduleOrFail(envVarName, ",")
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvNamedDurationsOrFail_ParsesPairs(t *testing.T) {
+	t.Setenv(envVarName, "mon=9h, fri = 17h30m,")
+
+	actualValue, err := GetEnvNamedDurationsOrFail(envVarName, ",", "=")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"mon": 9 * time.Hour,
+		"fri": 17*time.Hour + 30*time.Minute,
+	}, actualValue)
+}
+
+func TestGetEnvNamedDurationsOrFail_FailsOnInvalidDurationWithPosition(t *testing.T) {
+	t.Setenv(envVarName, "mon:9h;fri:5pm")
+
+	_, err := GetEnvNamedDurationsOrFail(envVarName, ";", ":")
+
+	assert.ErrorContains(t, err, "pair 1 'fri:5pm' of '"+envVarName+"' has an invalid duration")
+}
+
+func TestGetEnvNamedDurationsOrFail_FailsOnBadKey(t *testing.T) {
+	tests := map[string]string{
+		"mon=9h,=17h":    "pair 1 '=17h' of '" + envVarName + "' has an empty key",
+		"mon=9h,mon=17h": "pair 1 'mon=17h' of '" + envVarName + "' repeats the key 'mon'",
+		"mon=9h,fri":     "pair 1 'fri' of '" + envVarName + "' is missing the separator '='",
+	}
+	for val, expectedError := range tests {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvNamedDurationsOrFail(envVarName, ",", "=")
+
+		assert.EqualError(t, err, expectedError, val)
+	}
+}
+
+func TestGetEnvNamedDurationsOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvNamedDurationsOrFail(envVarName, ",", "=")
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvCountOrDurationOrFail_ParsesDuration(t *testing.T) {
+	t.Setenv(envVarName, "30s")
+
+	count, dur, isDuration, err := GetEnvCountOrDurationOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.True(t, isDuration)
+	assert.Equal(t, 30*time.Second, dur)
+	assert.Equal(t, 0, count)
+}
+
+func TestGetEnvCountOrDurationOrFail_ParsesCount(t *testing.T) {
+	t.Setenv(envVarName, "100")
+
+	count, dur, isDuration, err := GetEnvCountOrDurationOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.False(t, isDuration)
+	assert.Equal(t, 100, count)
+	assert.Equal(t, time.Duration(0), dur)
+}
+
+func TestGetEnvCountOrDurationOrFail_ReportsZeroAsDuration(t *testing.T) {
+	t.Setenv(envVarName, "0")
+
+	_, _, isDuration, err := GetEnvCountOrDurationOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.True(t, isDuration)
+}
+
+func TestGetEnvCountOrDurationOrFail_FailsIfNeither(t *testing.T) {
+	t.Setenv(envVarName, "ten")
+
+	_, _, _, err := GetEnvCountOrDurationOrFail(envVarName)
+
+	assert.EqualError(t, err, "value 'ten' for '"+envVarName+
+		"' is neither a valid duration nor a valid count")
+}
+
+func TestGetEnvCountOrDurationOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, _, _, err := GetEnvCountOrDurationOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvISODurationOrFail_ParsesComponents(t *testing.T) {
+	tests := map[string]time.Duration{
+		"PT1H30M":   90 * time.Minute,
+		"P1DT12H":   36 * time.Hour,
+		"P2D":       48 * time.Hour,
+		"PT0.5S":    500 * time.Millisecond,
+		"PT1,25S":   1250 * time.Millisecond,
+		"-PT10M":    -10 * time.Minute,
+		"PT1H2M3S":  time.Hour + 2*time.Minute + 3*time.Second,
+		"PT90M":     90 * time.Minute,
+		"P0D":       0,
+		"PT0S":      0,
+		"P1DT0.5S":  24*time.Hour + 500*time.Millisecond,
+		"PT36H":     36 * time.Hour,
+		"PT100000S": 100000 * time.Second,
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvISODurationOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, expected, actualValue, val)
+	}
+}
+
+func TestGetEnvISODurationOrFail_FailsOnMalformedInput(t *testing.T) {
+	for _, val := range []string{"P", "PT", "P1DT", "1H", "PT1.5H2", "P1Y", "P1W", "PT1M1H"} {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvISODurationOrFail(envVarName)
+
+		assert.ErrorContains(t, err,
+			"value '"+val+"' for '"+envVarName+"' is not a valid ISO 8601 duration", val)
+	}
+}
+
+func TestGetEnvISODurationOrFail_FailsIfOutOfRange(t *testing.T) {
+	t.Setenv(envVarName, "P1000000D")
+
+	_, err := GetEnvISODurationOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "duration out of range")
+}
+
+func TestGetEnvISODurationOrFail_AcceptsMaximumAndFailsBeyond(t *testing.T) {
+	for _, val := range []string{"PT9223372036.854775807S", "P106751DT23H47M16.854775807S"} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvISODurationOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, time.Duration(math.MaxInt64), actualValue, val)
+	}
+	for _, val := range []string{"PT9223372036.854775808S", "P106751DT23H47M16.854775808S"} {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvISODurationOrFail(envVarName)
+
+		assert.ErrorContains(t, err, "duration out of range", val)
+	}
+}
+
+func TestGetEnvISODurationOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvISODurationOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvISODurationOrDefault_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "PT5M")
+
+	actualValue := GetEnvISODurationOrDefault(envVarName, time.Second)
+
+	assert.Equal(t, 5*time.Minute, actualValue)
+}
+
+func TestGetEnvISODurationOrDefault_ReturnsDefaultIfMalformedOrNotSet(t *testing.T) {
+	for _, val := range []string{"", "5m"} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvISODurationOrDefault(envVarName, time.Second)
+
+		assert.Equal(t, time.Second, actualValue, val)
+	}
+}
@@ -15,46 +15,97 @@
 
 // Package envtools provides helper functions for getting vars or secrets
 // from environment variables.
-// It uses logrus as logger.
+// It uses logrus as logger by default, see SetLogger.
 package envtools
 
 import (
 	"fmt"
-	"os"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
-var logger = logrus.StandardLogger()
+var logger = newRedactingLogger(logrus.StandardLogger())
 
-func SetLogger(newLogger *logrus.Logger) {
-	logger = newLogger
+// SetLogger replaces the logger used by this package, which defaults to
+// logrus.StandardLogger().
+func SetLogger(newLogger Logger) {
+	logger = newRedactingLogger(newLogger)
 }
 
 // GetEnvOrWarn looks up the environment variable with the provided name.
 // If the variable is set, its value is returned.
 // Otherwise, a warning message will be logged.
 func GetEnvOrWarn(envName string) string {
-	val := os.Getenv(envName)
+	val := getenv(envName)
 	if len(val) == 0 {
-		logger.Warnf("environment variable '%v' is not set", envName)
+		logger.withEnv(envName).Warnf("environment variable '%v' %v", envName, envState(envName))
+		emitConfigEvent(envName, ConfigEventMissing, nil)
 	} else {
-		logger.Infof("using configured value '%v' for '%v'", val, envName)
+		logValue(envName, val)
 	}
 	return val
 }
 
+// LookupEnvOrWarn looks up the environment variable with the provided name.
+// Unlike GetEnvOrWarn, it distinguishes a variable that is not set from one
+// that is set to an empty value, which may be a valid configuration: found
+// is only false if the variable is not set, in which case a warning message
+// will be logged.
+func LookupEnvOrWarn(envName string) (value string, found bool) {
+	val, found := lookupEnv(envName)
+	switch {
+	case !found:
+		logger.withEnv(envName).Warnf("environment variable '%v' %v", envName, envNotSet)
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+	case len(val) == 0:
+		logger.withEnv(envName).Infof("environment variable '%v' %v", envName, envSetButEmpty)
+		emitConfigEvent(envName, ConfigEventOK, val)
+	default:
+		logValue(envName, val)
+	}
+	return val, found
+}
+
 // GetEnvSecretOrWarn looks up the environment variable with the provided name.
 // If the variable is set, its value is returned.
 // Otherwise, a warning message will be logged.
 // The difference to GetEnvOrWarn is that the extracted value is masked by "*".
 func GetEnvSecretOrWarn(envName string) string {
-	val := os.Getenv(envName)
+	val := getenv(envName)
 	if len(val) == 0 {
-		logger.Warnf("environment variable '%v' is not set", envName)
+		logger.withEnv(envName).Warnf("environment variable '%v' %v", envName, envState(envName))
+		emitConfigEvent(envName, ConfigEventMissing, nil)
 	} else {
-		logger.Infof("using configured secret '**********' for '%v'", envName)
+		logSecret(envName, val)
+	}
+	return val
+}
+
+// GetEnvSecretMaskedOrWarn looks up the environment variable with the
+// provided name like GetEnvSecretOrWarn, but logs the last revealLast
+// characters of the value, e.g. "******cd12", to tell which secret is loaded.
+// At most half of the characters are revealed. If the value is not longer
+// than revealLast, it is masked completely. The returned value is unmasked.
+func GetEnvSecretMaskedOrWarn(envName string, revealLast int) string {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logger.withEnv(envName).Warnf("environment variable '%v' %v", envName, envState(envName))
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		return val
 	}
+	if !secretMaskingEnabled {
+		logSecret(envName, val)
+		return val
+	}
+	logger.withEnv(envName).Infof(
+		"using configured secret '%v' for '%v'", maskRevealingSuffix(val, revealLast), envName,
+	)
+	emitConfigEvent(envName, ConfigEventOK, secretMask)
 	return val
 }
 
@@ -62,32 +113,175 @@ func GetEnvSecretOrWarn(envName string) string {
 // If the variable is set, its value is returned.
 // Otherwise, the provided defaultValue will be returned.
 func GetEnvOrDefault(envName string, defaultValue string) string {
-	val := os.Getenv(envName)
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	logValue(envName, val)
+	return val
+}
+
+// GetEnvSecretOrDefault looks up the environment variable with the provided
+// name. If the variable is set, its value is returned.
+// Otherwise, the provided defaultValue will be returned.
+// The difference to GetEnvOrDefault is that both the extracted value and the
+// defaultValue are masked by "*".
+func GetEnvSecretOrDefault(envName string, defaultValue string) string {
+	val := getenv(envName)
 	if len(val) == 0 {
-		logger.Infof(
-			"environment variable '%v' is not set, defaulting to %v",
+		logger.withEnv(envName).Infof(
+			"environment variable '%v' %v, using default secret '%v'",
 			envName,
-			defaultValue,
+			envState(envName),
+			maskSecret(defaultValue),
 		)
+		emitConfigEvent(envName, ConfigEventDefaulted, secretMask)
 		return defaultValue
 	}
-	logger.Infof("using configured value '%v' for '%v'", val, envName)
+	logSecret(envName, val)
 	return val
 }
 
-// GetEnvOrFail looks up an environment variable. If the environment
-// variable is not set or empty, an error is returned.
-func GetEnvOrFail(envName string) (string, error) {
-	val := os.Getenv(envName)
+// GetEnvTrimmedOrDefault looks up the environment variable with the provided
+// name and removes leading and trailing white space from its value. If the
+// trimmed value is not empty, it is returned. Otherwise, e.g. if the variable
+// is set to " " by a quoted empty string with a trailing space, the provided
+// defaultValue will be returned.
+func GetEnvTrimmedOrDefault(envName string, defaultValue string) string {
+	raw := getenv(envName)
+	val := strings.TrimSpace(raw)
 	if len(val) == 0 {
-		msg := fmt.Sprintf(
-			"please set the environment variable '%s'",
+		if len(raw) != 0 {
+			logger.withEnv(envName).Infof(
+				"environment variable '%v' is blank after trimming, defaulting to %v",
+				envName,
+				defaultValue,
+			)
+			emitConfigEvent(envName, ConfigEventDefaulted, defaultValue)
+		} else {
+			logDefault(envName, defaultValue)
+		}
+		return defaultValue
+	}
+	logValue(envName, val)
+	return val
+}
+
+// GetEnvTrimCutsetOrDefault looks up the environment variable with the
+// provided name and removes all leading and trailing characters contained in
+// cutset from its value, as strings.Trim does, e.g. trailing slashes of a base
+// URL with cutset "/". If the trimmed value is not empty, it is logged and
+// returned. Otherwise, the provided defaultValue will be returned.
+func GetEnvTrimCutsetOrDefault(envName, defaultValue, cutset string) string {
+	raw := getenv(envName)
+	val := strings.Trim(raw, cutset)
+	if len(val) == 0 {
+		if len(raw) != 0 {
+			logger.withEnv(envName).Infof(
+				"environment variable '%v' is empty after trimming '%v', defaulting to %v",
+				envName,
+				cutset,
+				defaultValue,
+			)
+			emitConfigEvent(envName, ConfigEventDefaulted, defaultValue)
+		} else {
+			logDefault(envName, defaultValue)
+		}
+		return defaultValue
+	}
+	logValue(envName, val)
+	return val
+}
+
+// GetEnvTrimCutsetOrFail looks up an environment variable and trims it like
+// GetEnvTrimCutsetOrDefault does. If the environment variable is not set or
+// empty, or if the value is empty after trimming, an error is returned.
+func GetEnvTrimCutsetOrFail(envName, cutset string) (string, error) {
+	raw, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	val := strings.Trim(raw, cutset)
+	if len(val) == 0 {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is empty after trimming '%s'", raw, envName, cutset,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// GetEnvCanonicalOrDefault looks up the environment variable with the provided
+// name like GetEnvOrDefault and converts the value, or the defaultValue if
+// the variable is not set, to its canonical form using canonicalize. If a
+// configured value was not already canonical, a warning suggests updating
+// the source, so that configuration becomes consistent over time. The
+// returned value is always canonical.
+func GetEnvCanonicalOrDefault(
+	envName string,
+	defaultValue string,
+	canonicalize func(string) string,
+) string {
+	val := getenv(envName)
+	if len(val) == 0 {
+		canonical := canonicalize(defaultValue)
+		logDefault(envName, canonical)
+		return canonical
+	}
+	canonical := canonicalize(val)
+	if canonical != val {
+		logger.withEnv(envName).Warnf(
+			"value '%v' for '%v' is not in canonical form, please change it to '%v'",
+			val,
 			envName,
+			canonical,
 		)
-		logger.Errorln(msg)
-		return "", fmt.Errorf(msg)
 	}
-	logger.Infof("using configured value '%v' for '%v'", val, envName)
+	logValue(envName, canonical)
+	return canonical
+}
+
+// GetEnvWithPlatformFallback looks up the app specific environment variable
+// appName first. If it is not set, the platform provided variable platformName
+// (e.g. following the OTEL_ or KUBERNETES_ conventions) is looked up instead.
+// If neither is set, the provided defaultValue will be returned.
+// The log message states which source was used.
+func GetEnvWithPlatformFallback(appName, platformName, defaultValue string) string {
+	if val := getenv(appName); len(val) != 0 {
+		logValue(appName, val)
+		return val
+	}
+	if val := getenv(platformName); len(val) != 0 {
+		logger.withEnv(appName).Infof(
+			"environment variable '%v' %v, using platform value '%v' of '%v'",
+			appName,
+			envState(appName),
+			val,
+			platformName,
+		)
+		emitConfigEvent(appName, ConfigEventOK, val)
+		return val
+	}
+	logger.withEnv(appName).Infof(
+		"environment variables '%v' and '%v' are not set, defaulting to %v",
+		appName,
+		platformName,
+		defaultValue,
+	)
+	emitConfigEvent(appName, ConfigEventDefaulted, defaultValue)
+	return defaultValue
+}
+
+// GetEnvOrFail looks up an environment variable. If the environment
+// variable is not set or empty, an error is returned.
+func GetEnvOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	logValue(envName, val)
 
 	return val, nil
 }
@@ -95,30 +289,75 @@ func GetEnvOrFail(envName string) (string, error) {
 // GetEnvSecretOrFail looks up an environment variable. If the environment
 // variable is not set or empty, an error is returned.
 func GetEnvSecretOrFail(envName string) (string, error) {
-	val := os.Getenv(envName)
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	logSecret(envName, val)
+
+	return val, nil
+}
+
+// requireEnv looks up an environment variable. If the environment
+// variable is not set or empty, the error is logged and returned.
+func requireEnv(envName string) (string, error) {
+	val, found := lookupEnv(envName)
 	if len(val) == 0 {
+		state := envNotSet
+		if found {
+			state = envSetButEmpty
+		}
 		msg := fmt.Sprintf(
 			"please set the environment variable '%s'",
 			envName,
 		)
-		logger.Errorln(msg)
+		logger.withEnv(envName).Errorln(msg + ", it " + state)
+		emitConfigEvent(envName, ConfigEventMissing, nil)
 		return "", fmt.Errorf(msg)
 	}
-	logger.Infof("using configured secret '**********' for '%v'", envName)
-
 	return val, nil
 }
 
+// States of an environment variable without value, as used in log messages.
+const (
+	envNotSet       = "is not set"
+	envSetButEmpty  = "is set but empty"
+	envSetButUnused = "is set but has no usable value"
+)
+
+// envState describes why the environment variable with the provided name has
+// no value for log messages, distinguishing a variable that is not set from
+// one that is explicitly set to an empty value. For a non-empty value, e.g.
+// one consisting of separators only, envSetButUnused is returned.
+func envState(envName string) string {
+	val, found := peekEnv(envName)
+	switch {
+	case !found:
+		return envNotSet
+	case len(val) == 0:
+		return envSetButEmpty
+	default:
+		return envSetButUnused
+	}
+}
+
+// logError logs the provided error and returns it unchanged.
+func logError(err error) error {
+	logger.Errorln(err)
+	return err
+}
+
 // GetEnvOrPanic looks up an environment variable. If the environment
 // variable is not set, it panics.
 func GetEnvOrPanic(envName string) string {
-	value := os.Getenv(envName)
+	value := getenv(envName)
 	if len(value) == 0 {
 		msg := fmt.Sprintf("please set the environment variable '%s'", envName)
-		logger.Panicln(msg)
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		logger.withEnv(envName).Panicln(msg + ", it " + envState(envName))
 		panic(msg)
 	}
-	logger.Infof("using configured value '%v' for '%v'", value, envName)
+	logValue(envName, value)
 
 	return value
 }
@@ -127,13 +366,214 @@ func GetEnvOrPanic(envName string) string {
 // variable is not set, it panics.
 // The difference to GetEnvOrPanic is that the extracted value is masked by "*".
 func GetEnvSecretOrPanic(envName string) string {
-	value := os.Getenv(envName)
+	value := getenv(envName)
 	if len(value) == 0 {
 		msg := fmt.Sprintf("please set the environment variable '%s'", envName)
-		logger.Panicln(msg)
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		logger.withEnv(envName).Panicln(msg + ", it " + envState(envName))
 		panic(msg)
 	}
-	logger.Infof("using configured secret '**********' for '%v'", envName)
+	logSecret(envName, value)
 
 	return value
 }
+
+// GetEnvOrBuildDefault looks up the environment variable with the provided name.
+// If the variable is set, its value is returned.
+// Otherwise, the provided buildDefault will be returned. It is meant for
+// defaults baked in at build time, e.g. via -ldflags "-X ...", and the log
+// message states this to distinguish it from a default given at runtime.
+func GetEnvOrBuildDefault(envName string, buildDefault string) string {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logger.withEnv(envName).Infof(
+			"environment variable '%v' %v, using build-time default %v",
+			envName,
+			envState(envName),
+			buildDefault,
+		)
+		emitConfigEvent(envName, ConfigEventDefaulted, buildDefault)
+		return buildDefault
+	}
+	logValue(envName, val)
+	return val
+}
+
+var (
+	weightedRandomMu sync.Mutex
+	// weightedRandom is seeded explicitly, as the global source of math/rand
+	// is not seeded before Go 1.20, which would make every process choose
+	// the same default. It is not safe for concurrent use.
+	// nolint: gosec // The choice is not security relevant.
+	weightedRandom = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// GetEnvOrWeightedRandomDefault looks up the environment variable with the
+// provided name. If the variable is set, its value is returned.
+// Otherwise, one of the choices is picked randomly as default, with choices
+// mapping each candidate value to its weight. This is meant for chaos and
+// testing features which should exercise different code paths. Choices with
+// a weight less than or equal to zero are never picked. If there is no such
+// choice, an empty string is returned.
+func GetEnvOrWeightedRandomDefault(envName string, choices map[string]int) string {
+	val := getenv(envName)
+	if len(val) != 0 {
+		logValue(envName, val)
+		return val
+	}
+	candidates := make([]string, 0, len(choices))
+	totalWeight := 0
+	for candidate, weight := range choices {
+		if weight > 0 {
+			candidates = append(candidates, candidate)
+			totalWeight += weight
+		}
+	}
+	if totalWeight == 0 {
+		logger.withEnv(envName).Warnf(
+			"environment variable '%v' %v and there is no random default to choose",
+			envName,
+			envState(envName),
+		)
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		return ""
+	}
+	// Sorting makes the choice depend on the random number only, not on the
+	// iteration order of the map.
+	sort.Strings(candidates)
+	weightedRandomMu.Lock()
+	pick := weightedRandom.Intn(totalWeight)
+	weightedRandomMu.Unlock()
+	for _, candidate := range candidates {
+		pick -= choices[candidate]
+		if pick < 0 {
+			val = candidate
+			break
+		}
+	}
+	logger.withEnv(envName).Infof(
+		"environment variable '%v' %v, defaulting to randomly chosen %v",
+		envName,
+		envState(envName),
+		val,
+	)
+	emitConfigEvent(envName, ConfigEventDefaulted, val)
+	return val
+}
+
+var (
+	recommendationWarningsMu sync.Mutex
+	recommendationWarnings   = map[string]bool{}
+)
+
+// GetEnvOrDefaultWithRecommendation looks up the environment variable like
+// GetEnvOrDefault does. If the effective value, i.e. the configured or the
+// default value, differs from the provided recommended value, a warning
+// suggesting the recommended value is logged. The warning is logged at most
+// once per variable.
+func GetEnvOrDefaultWithRecommendation(envName, defaultValue, recommended string) string {
+	val := GetEnvOrDefault(envName, defaultValue)
+	if val == recommended {
+		return val
+	}
+	recommendationWarningsMu.Lock()
+	defer recommendationWarningsMu.Unlock()
+	if !recommendationWarnings[envName] {
+		recommendationWarnings[envName] = true
+		logger.withEnv(envName).Warnf(
+			"effective value '%v' for '%v' differs from the recommended value '%v'",
+			val,
+			envName,
+			recommended,
+		)
+	}
+	return val
+}
+
+// GetEnvFirstLineOrDefault looks up the environment variable with the provided
+// name and returns only its first line, with surrounding whitespace trimmed.
+// This tolerates injection mechanisms that append a trailing newline plus
+// metadata to the actual value.
+// If the variable is not set or its first line is empty, the provided
+// defaultValue will be returned.
+func GetEnvFirstLineOrDefault(envName string, defaultValue string) string {
+	val := firstLine(getenv(envName))
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	logValue(envName, val)
+	return val
+}
+
+// GetEnvSecretFirstLineOrDefault behaves like GetEnvFirstLineOrDefault.
+// The difference is that neither the extracted value nor the default is
+// logged, both are masked by "*".
+func GetEnvSecretFirstLineOrDefault(envName string, defaultValue string) string {
+	val := firstLine(getenv(envName))
+	if len(val) == 0 {
+		logger.withEnv(envName).Infof(
+			"environment variable '%v' %v, defaulting to '%v'",
+			envName,
+			envState(envName),
+			maskSecret(defaultValue),
+		)
+		emitConfigEvent(envName, ConfigEventDefaulted, secretMask)
+		return defaultValue
+	}
+	logSecret(envName, val)
+	return val
+}
+
+// firstLine returns the content of s up to the first newline, trimmed.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return strings.TrimSpace(line)
+}
+
+// ConfigKeyToEnvName converts a dotted config key like "server.http.port" to
+// the environment variable naming convention, i.e. "SERVER_HTTP_PORT".
+func ConfigKeyToEnvName(dottedKey string) string {
+	return strings.ToUpper(strings.ReplaceAll(dottedKey, ".", "_"))
+}
+
+// GetConfigKeyOrDefault converts the dotted config key to the name of an
+// environment variable using ConfigKeyToEnvName and looks it up like
+// GetEnvOrDefault does.
+func GetConfigKeyOrDefault(dottedKey, defaultValue string) string {
+	return GetEnvOrDefault(resolveConfigKey(dottedKey), defaultValue)
+}
+
+// GetConfigKeyIntOrDefault converts the dotted config key like
+// GetConfigKeyOrDefault does and looks it up like GetEnvIntOrDefault does.
+func GetConfigKeyIntOrDefault(dottedKey string, defaultValue int) int {
+	return GetEnvIntOrDefault(resolveConfigKey(dottedKey), defaultValue)
+}
+
+// GetConfigKeyBoolOrDefault converts the dotted config key like
+// GetConfigKeyOrDefault does and looks it up like GetEnvBoolOrDefault does.
+func GetConfigKeyBoolOrDefault(dottedKey string, defaultValue bool) bool {
+	return GetEnvBoolOrDefault(resolveConfigKey(dottedKey), defaultValue)
+}
+
+// GetConfigKeyDurationOrDefault converts the dotted config key like
+// GetConfigKeyOrDefault does and looks it up like GetEnvDurationOrDefault
+// does.
+func GetConfigKeyDurationOrDefault(
+	dottedKey string,
+	defaultValue time.Duration,
+) time.Duration {
+	return GetEnvDurationOrDefault(resolveConfigKey(dottedKey), defaultValue)
+}
+
+// resolveConfigKey converts the dotted config key to the name of an
+// environment variable and logs the result.
+func resolveConfigKey(dottedKey string) string {
+	envName := ConfigKeyToEnvName(dottedKey)
+	logger.withEnv(envName).Debugf(
+		"resolved config key '%v' to environment variable '%v'",
+		dottedKey,
+		envName,
+	)
+	return envName
+}
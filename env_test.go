@@ -19,7 +19,9 @@ package envtools
 import (
 	"bytes"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -82,7 +84,7 @@ func TestGetEnvOrWarn_WarnsIfEmptyEnvVarSet(t *testing.T) {
 
 	actualValue := GetEnvOrWarn(envVarName)
 
-	const expectedOutput = "environment variable '" + envVarName + "' is not set"
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty"
 	assert.Equal(t, expectedValue, actualValue)
 	assert.Contains(t, buf.String(), expectedOutput)
 }
@@ -126,7 +128,7 @@ func TestGetEnvSecretOrWarn_ReturnsEmptyStringIfEmptyEnvVarSet(t *testing.T) {
 
 	actualValue := GetEnvSecretOrWarn(envVarName)
 
-	const expectedOutput = "environment variable '" + envVarName + "' is not set"
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty"
 	assert.Equal(t, expectedValue, actualValue)
 	assert.Contains(t, buf.String(), expectedOutput)
 }
@@ -154,7 +156,7 @@ func TestGetEnvOrDefault_ReturnsDefaultIfSetToEmptyString(t *testing.T) {
 
 	actualValue := GetEnvOrDefault(envVarName, expectedValue)
 
-	const expectedOutput = "environment variable '" + envVarName + "' is not set," +
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty," +
 		" defaulting to Default Value"
 	assert.Equal(t, expectedValue, actualValue)
 	assert.Contains(t, buf.String(), expectedOutput)
@@ -177,6 +179,208 @@ func TestGetEnvOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
 	assert.Contains(t, buf.String(), expectedOutput)
 }
 
+func TestLookupEnvOrWarn_SucceedsIfSet(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue, found := LookupEnvOrWarn(envVarName)
+
+	assert.True(t, found)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestLookupEnvOrWarn_DistinguishesEmptyFromNotSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue, found := LookupEnvOrWarn(envVarName)
+
+	assert.True(t, found)
+	assert.Empty(t, actualValue)
+	assert.Contains(t, buf.String(), "level=info")
+	assert.Contains(t, buf.String(),
+		"environment variable '"+envVarName+"' is set but empty")
+
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	_, found = LookupEnvOrWarn(envVarName)
+
+	assert.False(t, found)
+	assert.Contains(t, buf.String(), "level=warning")
+	assert.Contains(t, buf.String(), "environment variable '"+envVarName+"' is not set")
+}
+
+func TestGetEnvSecretMaskedOrWarn_RevealsSuffix(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "ghp_abcdefcd12")
+
+	actualValue := GetEnvSecretMaskedOrWarn(envVarName, 4)
+
+	assert.Equal(t, "ghp_abcdefcd12", actualValue)
+	assert.Contains(t, buf.String(), "using configured secret '******cd12'")
+	assert.NotContains(t, buf.String(), "ghp_abcdef")
+}
+
+func TestGetEnvSecretMaskedOrWarn_WarnsIfEnvNotSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvSecretMaskedOrWarn(envVarName, 4)
+
+	assert.Empty(t, actualValue)
+	assert.Contains(t, buf.String(), "environment variable '"+envVarName+"' is set but empty")
+}
+
+func TestGetEnvSecretOrDefault_MasksConfiguredValue(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "s3cr3t")
+
+	actualValue := GetEnvSecretOrDefault(envVarName, "dev-token")
+
+	assert.Equal(t, "s3cr3t", actualValue)
+	assert.Contains(t, buf.String(), "using configured secret '**********'")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestGetEnvSecretOrDefault_MasksDefaultValue(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvSecretOrDefault(envVarName, "dev-token")
+
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty, " +
+		"using default secret '**********'"
+	assert.Equal(t, "dev-token", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+	assert.NotContains(t, buf.String(), "dev-token")
+}
+
+func TestGetEnvTrimmedOrDefault_ReturnsTrimmedValue(t *testing.T) {
+	t.Setenv(envVarName, "  "+expectedValue+"\t")
+
+	actualValue := GetEnvTrimmedOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvTrimmedOrDefault_ReturnsDefaultIfBlank(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, " \t ")
+
+	actualValue := GetEnvTrimmedOrDefault(envVarName, "Default Value")
+
+	const expectedOutput = "environment variable '" + envVarName + "' is blank after " +
+		"trimming, defaulting to Default Value"
+	assert.Equal(t, "Default Value", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvTrimmedOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvTrimmedOrDefault(envVarName, "Default Value")
+
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty," +
+		" defaulting to Default Value"
+	assert.Equal(t, "Default Value", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvTrimCutsetOrDefault_TrimsCutset(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "https://example.com/api//")
+
+	actualValue := GetEnvTrimCutsetOrDefault(envVarName, "http://localhost", "/")
+
+	assert.Equal(t, "https://example.com/api", actualValue)
+	assert.Contains(t, buf.String(), "using configured value 'https://example.com/api' for")
+}
+
+func TestGetEnvTrimCutsetOrDefault_ReturnsDefaultIfEmptyAfterTrimming(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "//")
+
+	actualValue := GetEnvTrimCutsetOrDefault(envVarName, "Default Value", "/")
+
+	assert.Equal(t, "Default Value", actualValue)
+	assert.Contains(t, buf.String(), "environment variable '"+envVarName+
+		"' is empty after trimming '/', defaulting to Default Value")
+}
+
+func TestGetEnvTrimCutsetOrFail_TrimsCutset(t *testing.T) {
+	t.Setenv(envVarName, "/base/path/")
+
+	actualValue, err := GetEnvTrimCutsetOrFail(envVarName, "/")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "base/path", actualValue)
+}
+
+func TestGetEnvTrimCutsetOrFail_FailsIfEmptyAfterTrimming(t *testing.T) {
+	t.Setenv(envVarName, "//")
+
+	_, err := GetEnvTrimCutsetOrFail(envVarName, "/")
+	assert.EqualError(t, err, "value '//' for '"+envVarName+"' is empty after trimming '/'")
+
+	t.Setenv(envVarName, "")
+
+	_, err = GetEnvTrimCutsetOrFail(envVarName, "/")
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvCanonicalOrDefault_WarnsIfNotCanonical(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "EU-West-1")
+
+	actualValue := GetEnvCanonicalOrDefault(envVarName, "us-east-1", strings.ToLower)
+
+	const expectedOutput = "value 'EU-West-1' for '" + envVarName + "' is not in " +
+		"canonical form, please change it to 'eu-west-1'"
+	assert.Equal(t, "eu-west-1", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvCanonicalOrDefault_DoesNotWarnIfCanonical(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "eu-west-1")
+
+	actualValue := GetEnvCanonicalOrDefault(envVarName, "us-east-1", strings.ToLower)
+
+	assert.Equal(t, "eu-west-1", actualValue)
+	assert.Empty(t, buf.String())
+}
+
+func TestGetEnvCanonicalOrDefault_CanonicalizesDefault(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvCanonicalOrDefault(envVarName, "US-East-1", strings.ToLower)
+
+	assert.Equal(t, "us-east-1", actualValue)
+}
+
 func TestGetEnvOrFail_SucceedsIfEnvSet(t *testing.T) {
 	t.Setenv(envVarName, expectedValue)
 
@@ -187,18 +391,26 @@ func TestGetEnvOrFail_SucceedsIfEnvSet(t *testing.T) {
 }
 
 func TestGetEnvOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
 	t.Setenv(envVarName, "")
 	err := os.Unsetenv(envVarName)
 	assert.NoError(t, err)
 
 	_, err = GetEnvOrFail(envVarName)
-	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.Contains(t, buf.String(), "'"+envVarName+"', it is not set")
 }
 
 func TestGetEnvOrFail_IndeedFailsIfEmptyEnvVarSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
 	t.Setenv(envVarName, "")
 	_, err := GetEnvOrFail(envVarName)
-	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.Contains(t, buf.String(), "'"+envVarName+"', it is set but empty")
 }
 
 func TestGetEnvSecretOrFail_SucceedsIfEnvSet(t *testing.T) {
@@ -276,3 +488,264 @@ func TestGetEnvSecretOrPanic_SucceedsIfSet(t *testing.T) {
 
 	assert.Equal(t, expectedValue, actualValue)
 }
+
+func TestGetEnvFirstLineOrDefault_ReturnsFirstLineOnly(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "  "+expectedValue+" \r\nissued-by: injector\n")
+
+	actualValue := GetEnvFirstLineOrDefault(envVarName, "Default Value")
+
+	const expectedOutput = "using configured value 'Not Empty' for " +
+		"'" + envVarName + "'"
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvFirstLineOrDefault_ReturnsDefaultIfFirstLineEmpty(t *testing.T) {
+	t.Setenv(envVarName, "\nsecond line")
+
+	actualValue := GetEnvFirstLineOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, "Default Value", actualValue)
+}
+
+func TestGetEnvFirstLineOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	actualValue := GetEnvFirstLineOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, "Default Value", actualValue)
+}
+
+func TestGetEnvSecretFirstLineOrDefault_MasksValue(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "s3cr3t\nmetadata")
+
+	actualValue := GetEnvSecretFirstLineOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, "s3cr3t", actualValue)
+	assert.Contains(t, buf.String(), "using configured secret '**********'")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestGetEnvSecretFirstLineOrDefault_MasksDefault(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvSecretFirstLineOrDefault(envVarName, "default-secret")
+
+	assert.Equal(t, "default-secret", actualValue)
+	assert.NotContains(t, buf.String(), "default-secret")
+}
+
+const platformVarName = "SOME_ARBITRARY_TEST_PLATFORM_VAR_NAME"
+
+func TestGetEnvWithPlatformFallback_PrefersAppVariable(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(platformVarName, "platform value")
+
+	actualValue := GetEnvWithPlatformFallback(envVarName, platformVarName, "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Contains(t, buf.String(), "using configured value 'Not Empty' for '"+envVarName+"'")
+}
+
+func TestGetEnvWithPlatformFallback_FallsBackToPlatformVariable(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	t.Setenv(platformVarName, "platform value")
+
+	actualValue := GetEnvWithPlatformFallback(envVarName, platformVarName, "Default Value")
+
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty, " +
+		"using platform value 'platform value' of '" + platformVarName + "'"
+	assert.Equal(t, "platform value", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvWithPlatformFallback_ReturnsDefaultIfNeitherSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	t.Setenv(platformVarName, "")
+
+	actualValue := GetEnvWithPlatformFallback(envVarName, platformVarName, "Default Value")
+
+	const expectedOutput = "environment variables '" + envVarName + "' and '" +
+		platformVarName + "' are not set, defaulting to Default Value"
+	assert.Equal(t, "Default Value", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvOrBuildDefault_SucceedsIfSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue := GetEnvOrBuildDefault(envVarName, "v1.2.3")
+
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Contains(t, buf.String(), "using configured value 'Not Empty' for '"+envVarName+"'")
+}
+
+func TestGetEnvOrBuildDefault_ReportsBuildTimeDefault(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvOrBuildDefault(envVarName, "v1.2.3")
+
+	const expectedOutput = "environment variable '" + envVarName + "' is set but empty, " +
+		"using build-time default v1.2.3"
+	assert.Equal(t, "v1.2.3", actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestConfigKeyToEnvName_ConvertsDottedKey(t *testing.T) {
+	assert.Equal(t, "SERVER_HTTP_PORT", ConfigKeyToEnvName("server.http.port"))
+	assert.Equal(t, "LOG_LEVEL", ConfigKeyToEnvName("Log.Level"))
+	assert.Equal(t, "PLAIN", ConfigKeyToEnvName("plain"))
+}
+
+func TestGetConfigKeyOrDefault_LooksUpResolvedEnvName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.DebugLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue := GetConfigKeyOrDefault("some.arbitrary.test.env.var.name", "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Contains(t, buf.String(), "resolved config key 'some.arbitrary.test.env.var.name' "+
+		"to environment variable '"+envVarName+"'")
+}
+
+func TestGetConfigKeyOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetConfigKeyOrDefault("some.arbitrary.test.env.var.name", "Default Value")
+
+	assert.Equal(t, "Default Value", actualValue)
+}
+
+func TestGetConfigKeyIntOrDefault_LooksUpResolvedEnvName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.DebugLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "42")
+
+	actualValue := GetConfigKeyIntOrDefault("some.arbitrary.test.env.var.name", 7)
+
+	assert.Equal(t, 42, actualValue)
+	assert.Contains(t, buf.String(), "resolved config key 'some.arbitrary.test.env.var.name' "+
+		"to environment variable '"+envVarName+"'")
+}
+
+func TestGetConfigKeyBoolOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	assert.True(t, GetConfigKeyBoolOrDefault("some.arbitrary.test.env.var.name", true))
+
+	t.Setenv(envVarName, "off")
+
+	assert.False(t, GetConfigKeyBoolOrDefault("some.arbitrary.test.env.var.name", true))
+}
+
+func TestGetConfigKeyDurationOrDefault_LooksUpResolvedEnvName(t *testing.T) {
+	t.Setenv(envVarName, "1m30s")
+
+	actualValue := GetConfigKeyDurationOrDefault("some.arbitrary.test.env.var.name", time.Second)
+
+	assert.Equal(t, 90*time.Second, actualValue)
+}
+
+func TestGetEnvOrWeightedRandomDefault_SucceedsIfSet(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue := GetEnvOrWeightedRandomDefault(envVarName, map[string]int{"a": 1})
+
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvOrWeightedRandomDefault_PicksOnlyWeightedChoices(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	choices := map[string]int{"a": 3, "b": 1, "never": 0, "negative": -1}
+
+	picked := map[string]int{}
+	for i := 0; i < 200; i++ {
+		picked[GetEnvOrWeightedRandomDefault(envVarName, choices)]++
+	}
+
+	assert.Equal(t, 200, picked["a"]+picked["b"])
+	assert.Greater(t, picked["a"], picked["b"])
+	assert.Contains(t, buf.String(), "environment variable '"+envVarName+"' is set but empty, "+
+		"defaulting to randomly chosen ")
+}
+
+func TestGetEnvOrWeightedRandomDefault_ReturnsEmptyStringWithoutChoices(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvOrWeightedRandomDefault(envVarName, map[string]int{"never": 0})
+
+	assert.Equal(t, "", actualValue)
+}
+
+func TestGetEnvOrDefaultWithRecommendation_WarnsOnceIfDifferent(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	const varName = envVarName + "_RECOMMENDATION"
+	t.Setenv(varName, "text")
+
+	assert.Equal(t, "text", GetEnvOrDefaultWithRecommendation(varName, "json", "json"))
+	assert.Equal(t, "text", GetEnvOrDefaultWithRecommendation(varName, "json", "json"))
+
+	const expectedOutput = "effective value 'text' for '" + varName +
+		"' differs from the recommended value 'json'"
+	assert.Equal(t, 1, strings.Count(buf.String(), expectedOutput))
+}
+
+func TestGetEnvOrDefaultWithRecommendation_WarnsIfDefaultDiffers(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	const varName = envVarName + "_RECOMMENDATION_DEFAULT"
+	t.Setenv(varName, "")
+
+	actualValue := GetEnvOrDefaultWithRecommendation(varName, "text", "json")
+
+	assert.Equal(t, "text", actualValue)
+	assert.Contains(t, buf.String(), "level=warning")
+}
+
+func TestGetEnvOrDefaultWithRecommendation_DoesNotWarnIfRecommended(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	const varName = envVarName + "_RECOMMENDATION_FOLLOWED"
+	t.Setenv(varName, "json")
+
+	actualValue := GetEnvOrDefaultWithRecommendation(varName, "text", "json")
+
+	assert.Equal(t, "json", actualValue)
+	assert.NotContains(t, buf.String(), "recommended")
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ConfigEventCategory classifies a ConfigEvent.
+type ConfigEventCategory string
+
+// Categories of a ConfigEvent.
+const (
+	// ConfigEventMissing is emitted if a variable without default is not set.
+	ConfigEventMissing ConfigEventCategory = "missing"
+	// ConfigEventInvalid is emitted if the value of a variable is invalid,
+	// regardless of whether a default is used instead or an error returned.
+	ConfigEventInvalid ConfigEventCategory = "invalid"
+	// ConfigEventDefaulted is emitted if a variable is not set and its default
+	// is used.
+	ConfigEventDefaulted ConfigEventCategory = "defaulted"
+	// ConfigEventOK is emitted if the configured value of a variable is used.
+	ConfigEventOK ConfigEventCategory = "ok"
+)
+
+// ConfigEvent describes the outcome of looking up an environment variable.
+type ConfigEvent struct {
+	// Name is the name of the environment variable.
+	Name string
+	// Category classifies the outcome.
+	Category ConfigEventCategory
+	// Value is the used value for ConfigEventOK and ConfigEventDefaulted and
+	// the rejected value for ConfigEventInvalid of getters with a default.
+	// It is empty for ConfigEventMissing and for errors, which state the
+	// problem themselves. For variables whose name suggests a secret, e.g.
+	// because it contains "PASSWORD" or "TOKEN", and for values read as
+	// secret, it is masked by "*".
+	Value string
+}
+
+var (
+	configEventHandlersMu sync.RWMutex
+	configEventHandlers   []func(ConfigEvent)
+)
+
+// RegisterConfigEventHandler registers a handler that is called for every
+// lookup done by the getters of this package, alongside the normal logging,
+// e.g. to alert on configuration problems. Handlers are called synchronously
+// in the order of registration, so they should return quickly.
+func RegisterConfigEventHandler(handler func(ConfigEvent)) {
+	configEventHandlersMu.Lock()
+	defer configEventHandlersMu.Unlock()
+	configEventHandlers = append(configEventHandlers, handler)
+}
+
+// emitConfigEvent calls all registered handlers with the event for envName.
+// The value is redacted and, if envName suggests a secret, masked.
+func emitConfigEvent(envName string, category ConfigEventCategory, val interface{}) {
+	configEventHandlersMu.RLock()
+	handlers := configEventHandlers
+	configEventHandlersMu.RUnlock()
+	if len(handlers) == 0 {
+		return
+	}
+	event := ConfigEvent{Name: envName, Category: category}
+	if val != nil {
+		event.Value = redact(fmt.Sprint(val))
+		if isSecretName(envName) {
+			event.Value = secretMask
+		}
+	}
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
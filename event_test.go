@@ -0,0 +1,109 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordConfigEventsAndTearDown registers a handler recording all config
+// events and removes it when the test finishes.
+func recordConfigEventsAndTearDown(t *testing.T) *[]ConfigEvent {
+	var events []ConfigEvent
+	configEventHandlersMu.Lock()
+	previous := configEventHandlers
+	configEventHandlersMu.Unlock()
+	RegisterConfigEventHandler(func(event ConfigEvent) {
+		events = append(events, event)
+	})
+	t.Cleanup(func() {
+		configEventHandlersMu.Lock()
+		defer configEventHandlersMu.Unlock()
+		configEventHandlers = previous
+	})
+	return &events
+}
+
+func TestRegisterConfigEventHandler_ReportsCategories(t *testing.T) {
+	events := recordConfigEventsAndTearDown(t)
+
+	t.Setenv(envVarName, "42")
+	t.Setenv(otherVarName, "")
+	t.Setenv(minVarName, "forty-two")
+
+	GetEnvIntOrDefault(envVarName, 1)
+	GetEnvIntOrDefault(otherVarName, 1)
+	GetEnvIntOrDefault(minVarName, 1)
+	_, _ = GetEnvOrFail(otherVarName)
+	_, _ = GetEnvIntOrFail(minVarName)
+
+	assert.Equal(t, []ConfigEvent{
+		{Name: envVarName, Category: ConfigEventOK, Value: "42"},
+		{Name: otherVarName, Category: ConfigEventDefaulted, Value: "1"},
+		{Name: minVarName, Category: ConfigEventInvalid, Value: "forty-two"},
+		{Name: otherVarName, Category: ConfigEventMissing},
+		{Name: minVarName, Category: ConfigEventInvalid},
+	}, *events)
+}
+
+func TestRegisterConfigEventHandler_ReportsUnsetIntAsMissing(t *testing.T) {
+	events := recordConfigEventsAndTearDown(t)
+
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+	t.Setenv(minVarName, "")
+	t.Setenv(maxVarName, "many")
+
+	_, _ = GetEnvIntOrFail(envVarName)
+	_, _, _ = GetEnvIntRangePairOrFail(minVarName, maxVarName)
+
+	assert.Equal(t, []ConfigEvent{
+		{Name: envVarName, Category: ConfigEventMissing},
+		{Name: minVarName, Category: ConfigEventMissing},
+		{Name: maxVarName, Category: ConfigEventInvalid},
+	}, *events)
+}
+
+func TestRegisterConfigEventHandler_MasksSecrets(t *testing.T) {
+	events := recordConfigEventsAndTearDown(t)
+
+	t.Setenv(secretVarName, "s3cr3t")
+	t.Setenv(envVarName, "s3cr3t")
+
+	GetEnvOrDefault(secretVarName, "")
+	_, _ = GetEnvSecretOrFail(envVarName)
+
+	assert.Equal(t, []ConfigEvent{
+		{Name: secretVarName, Category: ConfigEventOK, Value: "**********"},
+		{Name: envVarName, Category: ConfigEventOK, Value: "**********"},
+	}, *events)
+}
+
+func TestRegisterConfigEventHandler_CallsHandlersInRegistrationOrder(t *testing.T) {
+	recordConfigEventsAndTearDown(t)
+	var calls []string
+	RegisterConfigEventHandler(func(ConfigEvent) { calls = append(calls, "first") })
+	RegisterConfigEventHandler(func(ConfigEvent) { calls = append(calls, "second") })
+
+	t.Setenv(envVarName, expectedValue)
+	GetEnvOrWarn(envVarName)
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"expvar"
+	"sync"
+)
+
+var expvarMu sync.Mutex
+
+// PublishExpvar publishes the current value of the environment variable with
+// the provided name as expvar.String of the same name, so it can be inspected
+// via the /debug/vars endpoint. Values of variables whose name suggests a
+// secret, e.g. because it contains "PASSWORD" or "TOKEN", are masked by "*".
+// Publishing a name again updates the value. If the name is already used by
+// an expvar of another type, a warning is logged and nothing is published.
+func PublishExpvar(envName string) {
+	val := getenv(envName)
+	if isSecretName(envName) {
+		val = secretMasker(val)
+	}
+
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	published := expvar.Get(envName)
+	if published == nil {
+		published = expvar.NewString(envName)
+	}
+	str, ok := published.(*expvar.String)
+	if !ok {
+		logger.withEnv(envName).Warnf(
+			"expvar '%v' is already published with another type, not publishing it",
+			envName,
+		)
+		return
+	}
+	str.Set(val)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishExpvar_UpdatesOnRepublish(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	PublishExpvar(envVarName)
+	assert.Equal(t, expectedValue, expvar.Get(envVarName).(*expvar.String).Value())
+
+	t.Setenv(envVarName, "Changed")
+	PublishExpvar(envVarName)
+	assert.Equal(t, "Changed", expvar.Get(envVarName).(*expvar.String).Value())
+}
+
+func TestPublishExpvar_MasksSecrets(t *testing.T) {
+	t.Setenv(secretVarName, "s3cr3t")
+
+	PublishExpvar(secretVarName)
+
+	assert.Equal(t, "**********", expvar.Get(secretVarName).(*expvar.String).Value())
+}
+
+func TestPublishExpvar_WarnsIfNameUsedByOtherType(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	if expvar.Get(otherVarName) == nil {
+		expvar.NewInt(otherVarName)
+	}
+	t.Setenv(otherVarName, "42")
+
+	PublishExpvar(otherVarName)
+
+	assert.Contains(t, buf.String(),
+		"expvar '"+otherVarName+"' is already published with another type")
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// GetEnvFileModeOrFail looks up an environment variable holding octal
+// permission bits like "0644" or "755" and returns them as os.FileMode.
+// If the environment variable is not set or empty, or if the value is not
+// octal or exceeds the permission bits 0777, an error is returned.
+func GetEnvFileModeOrFail(envName string) (os.FileMode, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	mode, err := parseFileMode(val)
+	if err != nil {
+		return 0, logEnvError(envName, fmt.Errorf("value '%s' for '%s' %w", val, envName, err))
+	}
+	logValue(envName, mode)
+
+	return mode, nil
+}
+
+// GetEnvFileModeOrDefault looks up an environment variable holding octal
+// permission bits like GetEnvFileModeOrFail does. If the variable is not set
+// or the value is invalid, the provided defaultValue will be returned.
+func GetEnvFileModeOrDefault(envName string, defaultValue os.FileMode) os.FileMode {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	mode, err := parseFileMode(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' %v, defaulting to %v",
+			val,
+			envName,
+			err,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, mode)
+	return mode
+}
+
+// parseFileMode parses octal permission bits. The returned error completes a
+// sentence starting with the value.
+func parseFileMode(val string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(val, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("is not a valid octal number")
+	}
+	if mode > uint64(os.ModePerm) {
+		return 0, fmt.Errorf("exceeds the permission bits %#o", os.ModePerm)
+	}
+	return os.FileMode(mode), nil
+}
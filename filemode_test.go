@@ -0,0 +1,87 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvFileModeOrFail_ParsesOctal(t *testing.T) {
+	tests := map[string]os.FileMode{
+		"0644": 0o644,
+		"644":  0o644,
+		"0755": 0o755,
+		"0":    0,
+		"777":  0o777,
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvFileModeOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, expected, actualValue, val)
+	}
+}
+
+func TestGetEnvFileModeOrFail_FailsIfNotOctal(t *testing.T) {
+	for _, val := range []string{"0648", "rw-r--r--", "-644"} {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvFileModeOrFail(envVarName)
+
+		assert.EqualError(t, err,
+			"value '"+val+"' for '"+envVarName+"' is not a valid octal number", val)
+	}
+}
+
+func TestGetEnvFileModeOrFail_FailsIfOutOfRange(t *testing.T) {
+	t.Setenv(envVarName, "4755")
+
+	_, err := GetEnvFileModeOrFail(envVarName)
+
+	assert.EqualError(t, err,
+		"value '4755' for '"+envVarName+"' exceeds the permission bits 0777")
+}
+
+func TestGetEnvFileModeOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvFileModeOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvFileModeOrDefault_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "0600")
+
+	actualValue := GetEnvFileModeOrDefault(envVarName, 0o644)
+
+	assert.Equal(t, os.FileMode(0o600), actualValue)
+}
+
+func TestGetEnvFileModeOrDefault_ReturnsDefaultIfInvalidOrNotSet(t *testing.T) {
+	for _, val := range []string{"", "999", "10000"} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvFileModeOrDefault(envVarName, 0o644)
+
+		assert.Equal(t, os.FileMode(0o644), actualValue, val)
+	}
+}
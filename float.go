@@ -0,0 +1,44 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"strconv"
+)
+
+// GetEnvFloatOrDefault looks up an environment variable and parses it as
+// float64, e.g. a sampling rate like "0.25". If the variable is not set, the
+// provided defaultValue will be returned. If the value cannot be parsed, a
+// warning is logged and the defaultValue will be returned as well.
+func GetEnvFloatOrDefault(envName string, defaultValue float64) float64 {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	value, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid float, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, value)
+	return value
+}
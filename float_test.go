@@ -0,0 +1,53 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvFloatOrDefault_ParsesFloat(t *testing.T) {
+	t.Setenv(envVarName, "0.25")
+
+	actualValue := GetEnvFloatOrDefault(envVarName, 1)
+
+	assert.Equal(t, 0.25, actualValue)
+}
+
+func TestGetEnvFloatOrDefault_ReturnsDefaultIfNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvFloatOrDefault(envVarName, 0.5)
+
+	assert.Equal(t, 0.5, actualValue)
+}
+
+func TestGetEnvFloatOrDefault_WarnsAndReturnsDefaultIfInvalid(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "quarter")
+
+	actualValue := GetEnvFloatOrDefault(envVarName, 0.5)
+
+	assert.Equal(t, 0.5, actualValue)
+	assert.Contains(t, buf.String(),
+		"value 'quarter' for '"+envVarName+"' is not a valid float, defaulting to 0.5")
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"strings"
+)
+
+// hostScopeSeparator separates the base name from the host suffix in the name
+// of a host scoped environment variable, e.g. "TIMEOUT__WORKER_1".
+const hostScopeSeparator = "__"
+
+// GetEnvForHostOrDefault looks up the host scoped environment variable
+// envName+"__"+HOST first, where HOST is the short name of the machine as
+// reported by os.Hostname, uppercased and with every character other than
+// ASCII letters and digits replaced by "_". On host "worker-1.example.com",
+// "TIMEOUT__WORKER_1" thus overrides "TIMEOUT". If the host scoped variable is
+// not set, envName is looked up instead. If neither is set, the provided
+// defaultValue will be returned. The log message states which name matched.
+func GetEnvForHostOrDefault(envName, defaultValue string) string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.withEnv(envName).Warnf("cannot determine hostname for '%v': %v", envName, err)
+	} else {
+		hostName := envName + hostScopeSeparator + hostSuffix(hostname)
+		if val := getenv(hostName); len(val) != 0 {
+			logValue(hostName, val)
+			return val
+		}
+	}
+	return GetEnvOrDefault(envName, defaultValue)
+}
+
+// hostSuffix normalizes hostname for use in the name of an environment
+// variable, see GetEnvForHostOrDefault.
+func hostSuffix(hostname string) string {
+	short, _, _ := strings.Cut(hostname, ".")
+	return strings.Map(func(r rune) rune {
+		if isASCIIAlphanumeric(r) {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(short))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvForHostOrDefault_PrefersHostScopedVariable(t *testing.T) {
+	hostname, err := os.Hostname()
+	assert.NoError(t, err)
+	t.Setenv(envVarName, "Base Value")
+	t.Setenv(envVarName+"__"+hostSuffix(hostname), expectedValue)
+
+	actualValue := GetEnvForHostOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvForHostOrDefault_FallsBackToBaseVariable(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue := GetEnvForHostOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvForHostOrDefault_ReturnsDefaultIfNeitherSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvForHostOrDefault(envVarName, "Default Value")
+
+	assert.Equal(t, "Default Value", actualValue)
+}
+
+func TestHostSuffix_NormalizesHostname(t *testing.T) {
+	assert.Equal(t, "WORKER_1", hostSuffix("worker-1.example.com"))
+	assert.Equal(t, "BUILD_H_ST", hostSuffix("build_höst"))
+	assert.Equal(t, "LOCALHOST", hostSuffix("localhost"))
+}
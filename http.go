@@ -0,0 +1,73 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// GetEnvHeadersOrFail looks up an environment variable holding HTTP headers
+// like "X-Api-Key: abc,X-Trace: on", where the headers are separated by pairSep
+// and each name is separated from its value by the first colon. Names are
+// canonicalized with textproto.CanonicalMIMEHeaderKey and values are trimmed.
+// Repeated names add further values. Values of auth-like headers, e.g.
+// Authorization, Cookie or X-Api-Key, are masked by "*" in the log message.
+// An error naming the fragment is returned if a header has no colon or an
+// empty name. An error is returned as well if the variable is not set or empty.
+func GetEnvHeadersOrFail(envName, pairSep string) (http.Header, error) {
+	if len(pairSep) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf("empty separator for '%s'", envName))
+	}
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	logged := http.Header{}
+	for _, fragment := range splitTrimmed(val, pairSep) {
+		name, value, found := strings.Cut(fragment, ":")
+		name = textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name))
+		if !found || len(name) == 0 {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"fragment '%s' of '%s' is not a header of the form 'Name: value'",
+				fragment,
+				envName,
+			))
+		}
+		value = strings.TrimSpace(value)
+		header.Add(name, value)
+		if isAuthHeader(name) {
+			value = maskSecret(value)
+		}
+		logged.Add(name, value)
+	}
+	logValue(envName, logged)
+
+	return header, nil
+}
+
+// isAuthHeader reports whether the canonical header name suggests that the
+// header carries credentials.
+func isAuthHeader(name string) bool {
+	switch name {
+	case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie":
+		return true
+	}
+	return isSecretName(name)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvHeadersOrFail_ParsesAndCanonicalizesHeaders(t *testing.T) {
+	t.Setenv(envVarName, "x-trace: on ;accept:application/json; ACCEPT: text/plain")
+
+	actualValue, err := GetEnvHeadersOrFail(envVarName, ";")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.Header{
+		"X-Trace": {"on"},
+		"Accept":  {"application/json", "text/plain"},
+	}, actualValue)
+}
+
+func TestGetEnvHeadersOrFail_KeepsColonsInValues(t *testing.T) {
+	t.Setenv(envVarName, "Referer: https://example.com:8443/,X-Trace: on")
+
+	actualValue, err := GetEnvHeadersOrFail(envVarName, ",")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com:8443/", actualValue.Get("Referer"))
+	assert.Equal(t, "on", actualValue.Get("X-Trace"))
+}
+
+func TestGetEnvHeadersOrFail_MasksAuthHeadersInLogs(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "X-Api-Key: abc,authorization: Bearer xyz,X-Trace: on")
+
+	actualValue, err := GetEnvHeadersOrFail(envVarName, ",")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", actualValue.Get("X-Api-Key"))
+	assert.Equal(t, "Bearer xyz", actualValue.Get("Authorization"))
+	assert.NotContains(t, buf.String(), "abc")
+	assert.NotContains(t, buf.String(), "xyz")
+	assert.Contains(t, buf.String(), "X-Trace:[on]")
+}
+
+func TestGetEnvHeadersOrFail_FailsOnMalformedFragment(t *testing.T) {
+	for _, val := range []string{"X-Trace: on,X-Debug", "X-Trace: on,: value"} {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvHeadersOrFail(envVarName, ",")
+
+		assert.ErrorContains(t, err, "of '"+envVarName+"' is not a header of the form", val)
+	}
+}
+
+func TestGetEnvHeadersOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvHeadersOrFail(envVarName, ",")
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kinds of values detected by InferType.
+const (
+	KindBool     = "bool"
+	KindInt      = "int"
+	KindFloat    = "float"
+	KindDuration = "duration"
+	KindString   = "string"
+)
+
+// InferType looks up an environment variable and detects the type of its
+// value, e.g. for a generic config UI which does not know the types ahead of
+// time. The parsers are tried in the following order and the first one
+// succeeding determines kind and the typed value:
+//  1. KindBool: "true" or "false", case-insensitive, as bool,
+//  2. KindInt: a base-10 integer as int,
+//  3. KindFloat: a finite floating point number as float64,
+//  4. KindDuration: a duration like "1m30s" as time.Duration,
+//  5. KindString: anything else as string.
+//
+// Note that "1" and "0" are detected as int, not as bool.
+// If the variable is not set or empty, found is false.
+func InferType(envName string) (kind string, value interface{}, found bool) {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logger.withEnv(envName).Infof("environment variable '%v' %v", envName, envState(envName))
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		return "", nil, false
+	}
+	kind, value = inferType(val)
+	logger.withEnv(envName).withValue(val).Infof(
+		"using configured value '%v' of kind %v for '%v'", val, kind, envName,
+	)
+	emitConfigEvent(envName, ConfigEventOK, val)
+	return kind, value, true
+}
+
+func inferType(val string) (string, interface{}) {
+	if strings.EqualFold(val, "true") || strings.EqualFold(val, "false") {
+		return KindBool, strings.EqualFold(val, "true")
+	}
+	if i, err := strconv.Atoi(val); err == nil {
+		return KindInt, i
+	}
+	if f, err := strconv.ParseFloat(val, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return KindFloat, f
+	}
+	if d, err := time.ParseDuration(val); err == nil {
+		return KindDuration, d
+	}
+	return KindString, val
+}
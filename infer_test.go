@@ -0,0 +1,61 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInferType_DetectsKindsInPrecedenceOrder(t *testing.T) {
+	tests := []struct {
+		val           string
+		expectedKind  string
+		expectedValue interface{}
+	}{
+		{"TRUE", KindBool, true},
+		{"false", KindBool, false},
+		{"1", KindInt, 1},
+		{"-42", KindInt, -42},
+		{"0.25", KindFloat, 0.25},
+		{"1e3", KindFloat, 1000.0},
+		{"1m30s", KindDuration, 90 * time.Second},
+		{"Inf", KindString, "Inf"},
+		{"yes", KindString, "yes"},
+		{expectedValue, KindString, expectedValue},
+	}
+	for _, test := range tests {
+		t.Setenv(envVarName, test.val)
+
+		kind, value, found := InferType(envVarName)
+
+		assert.True(t, found, test.val)
+		assert.Equal(t, test.expectedKind, kind, test.val)
+		assert.Equal(t, test.expectedValue, value, test.val)
+	}
+}
+
+func TestInferType_ReportsNotFound(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	kind, value, found := InferType(envVarName)
+
+	assert.False(t, found)
+	assert.Equal(t, "", kind)
+	assert.Nil(t, value)
+}
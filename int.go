@@ -0,0 +1,362 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetEnvIntOrDefault looks up an environment variable and parses it as base-10
+// integer. If the variable is not set, the provided defaultValue will be
+// returned. If the value cannot be parsed, a warning is logged and the
+// defaultValue will be returned as well.
+func GetEnvIntOrDefault(envName string, defaultValue int) int {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	value, err := strconv.Atoi(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid integer, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, value)
+	return value
+}
+
+// GetEnvIntOrDerived looks up an environment variable and parses it as base-10
+// integer. If the variable is not set or the value cannot be parsed, derive is
+// called to compute a fallback from other settings, e.g. a metrics port as
+// the application port plus 1000. If derive reports that it could not compute
+// a value either, a warning is logged and 0 is returned.
+func GetEnvIntOrDerived(envName string, derive func() (int, bool)) int {
+	val := getenv(envName)
+	if len(val) != 0 {
+		value, err := strconv.Atoi(val)
+		if err == nil {
+			logValue(envName, value)
+			return value
+		}
+		logInvalid(envName, val, "value '%v' for '%v' is not a valid integer", val, envName)
+	}
+	derived, ok := derive()
+	if !ok {
+		logger.withEnv(envName).Warnf("could not derive a value for '%v', defaulting to 0", envName)
+		emitConfigEvent(envName, ConfigEventDefaulted, 0)
+		return 0
+	}
+	logger.withEnv(envName).Infof(
+		"environment variable '%v' %v, using derived value %v",
+		envName,
+		envState(envName),
+		derived,
+	)
+	emitConfigEvent(envName, ConfigEventDefaulted, derived)
+	return derived
+}
+
+// GetEnvIntOrFail looks up an environment variable and parses it as base-10
+// integer. If the environment variable is not set or empty, or if the value
+// cannot be parsed, an error is returned.
+func GetEnvIntOrFail(envName string) (int, error) {
+	value, err := requireIntEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	logValue(envName, value)
+
+	return value, nil
+}
+
+// GetEnvIntBaseOrFail looks up an environment variable and parses it as
+// integer in the provided base, e.g. 16 for register masks or 8 for
+// permission bits. Base 0 detects the base from the prefix: "0x" for 16,
+// "0o" or "0" for 8, "0b" for 2 and 10 otherwise. If the environment variable
+// is not set or empty, or if the value has invalid digits for the base, an
+// error naming the (detected) base is returned.
+func GetEnvIntBaseOrFail(envName string, base int) (int64, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(val, base, 64)
+	if err != nil {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid integer in base %d",
+			val,
+			envName,
+			detectBase(val, base),
+		))
+	}
+	logValue(envName, value)
+
+	return value, nil
+}
+
+// detectBase returns the base strconv.ParseInt uses for val, i.e. base itself
+// or the base denoted by the prefix of val if base is 0.
+func detectBase(val string, base int) int {
+	if base != 0 {
+		return base
+	}
+	unsigned := strings.TrimLeft(val, "+-")
+	switch {
+	case len(unsigned) < 2 || unsigned[0] != '0':
+		return 10
+	case unsigned[1] == 'x' || unsigned[1] == 'X':
+		return 16
+	case unsigned[1] == 'b' || unsigned[1] == 'B':
+		return 2
+	default:
+		return 8
+	}
+}
+
+// GetEnvIntRangePairOrFail looks up two environment variables holding the
+// lower and upper bound of a range, e.g. MIN_WORKERS and MAX_WORKERS, and
+// parses both as base-10 integers. An error is returned if either variable
+// is not set or empty or cannot be parsed, listing the problems of both
+// variables, or if the lower bound is greater than the upper bound.
+func GetEnvIntRangePairOrFail(minVar, maxVar string) (minValue, maxValue int, err error) {
+	minValue, minErr := parseIntEnv(minVar)
+	maxValue, maxErr := parseIntEnv(maxVar)
+	var problems []string
+	for _, err := range []error{minErr, maxErr} {
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return 0, 0, logError(fmt.Errorf(
+			"invalid range '%s'-'%s': %s", minVar, maxVar, strings.Join(problems, "; "),
+		))
+	}
+	if minValue > maxValue {
+		return 0, 0, logError(fmt.Errorf(
+			"invalid range '%s'-'%s': minimum %d is greater than maximum %d",
+			minVar,
+			maxVar,
+			minValue,
+			maxValue,
+		))
+	}
+	logValue(minVar, minValue)
+	logValue(maxVar, maxValue)
+
+	return minValue, maxValue, nil
+}
+
+// GetEnvEnabledIfPositiveOrFail looks up an environment variable holding a
+// count that doubles as on/off switch, e.g. MAX_RETRIES where 0 disables
+// retries. It parses the value as base-10 integer and reports it as enabled
+// if it is positive. An error is returned if the variable is not set or
+// empty, if it cannot be parsed or if it is negative.
+func GetEnvEnabledIfPositiveOrFail(envName string) (enabled bool, value int, err error) {
+	value, err = requireIntEnv(envName)
+	if err != nil {
+		return false, 0, err
+	}
+	if value < 0 {
+		return false, 0, logEnvError(envName, fmt.Errorf(
+			"value '%d' for '%s' must not be negative", value, envName,
+		))
+	}
+	logValue(envName, value)
+
+	return value > 0, value, nil
+}
+
+// GetEnvIntSatisfyingOrFail looks up an environment variable, parses it as
+// base-10 integer and checks it with the provided predicate. The description
+// states the constraint for the error message, e.g. "must be a power of two".
+// An error is returned if the variable is not set or empty, if it cannot be
+// parsed or if the predicate is not satisfied.
+func GetEnvIntSatisfyingOrFail(
+	envName string,
+	predicate func(int) bool,
+	description string,
+) (int, error) {
+	value, err := requireIntEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	if !predicate(value) {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%d' for '%s' %s", value, envName, description,
+		))
+	}
+	logValue(envName, value)
+
+	return value, nil
+}
+
+// GetEnvIntOrDefaultStrictPresence looks up an environment variable and parses
+// it as base-10 integer. The variable must be present, but its value may be
+// wrong: if it is not set or empty, an error is returned, if it is set to a
+// malformed value, a warning is logged and defaultValue is returned.
+// Otherwise, the parsed value is returned.
+//
+// The four combinations of handling an absent and a malformed value are
+// provided by these getters:
+//   - absent fails, malformed fails: GetEnvIntOrFail,
+//   - absent defaults, malformed defaults: GetEnvIntOrDefault,
+//   - absent fails, malformed defaults: GetEnvIntOrDefaultStrictPresence,
+//   - absent defaults, malformed fails: GetTypedE with strconv.Atoi, which
+//     reports an absent variable by found being false, so the caller can
+//     apply its default, and returns an error for a malformed value.
+func GetEnvIntOrDefaultStrictPresence(envName string, defaultValue int) (int, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.Atoi(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid integer, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue, nil
+	}
+	logValue(envName, value)
+
+	return value, nil
+}
+
+// IntRange is an inclusive range of integers from Lo to Hi.
+type IntRange struct {
+	Lo int
+	Hi int
+}
+
+// Contains reports whether n is within the range.
+func (r IntRange) Contains(n int) bool {
+	return r.Lo <= n && n <= r.Hi
+}
+
+// IntRanges is a set of integers given by ranges, e.g. for ID allowlists.
+type IntRanges []IntRange
+
+// Contains reports whether n is within any of the ranges.
+func (r IntRanges) Contains(n int) bool {
+	for _, intRange := range r {
+		if intRange.Contains(n) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEnvIntRangesOrFail looks up an environment variable holding a list of
+// integer ranges like "1-5,10,20-25" separated by sep. Every element is
+// trimmed and is either a single base-10 integer, resulting in a range with
+// equal bounds, or an inclusive range "lo-hi". Empty elements are dropped.
+// An error naming the position of the offending element is returned if an
+// element is malformed or its lower bound is greater than its upper bound.
+// An error is returned as well if the variable is not set or empty or if it
+// contains no elements.
+func GetEnvIntRangesOrFail(envName, sep string) (IntRanges, error) {
+	elements, err := requireSlice(envName, sep)
+	if err != nil {
+		return nil, err
+	}
+	ranges := make(IntRanges, 0, len(elements))
+	for i, element := range elements {
+		intRange, err := parseIntRange(element)
+		if err != nil {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"element %d '%s' of '%s' is not a valid range: %w", i, element, envName, err,
+			))
+		}
+		ranges = append(ranges, intRange)
+	}
+	logValue(envName, ranges)
+
+	return ranges, nil
+}
+
+// parseIntRange parses a single integer or a range "lo-hi". The bounds may be
+// negative, e.g. "-5--1".
+func parseIntRange(val string) (IntRange, error) {
+	// The separating "-" is searched after the first character, which may be
+	// the sign of the lower bound.
+	lo, hi := val, val
+	if i := strings.Index(val[1:], "-"); i >= 0 {
+		lo, hi = strings.TrimSpace(val[:i+1]), strings.TrimSpace(val[i+2:])
+	}
+	loValue, err := strconv.Atoi(lo)
+	if err != nil {
+		return IntRange{}, fmt.Errorf("invalid lower bound '%s'", lo)
+	}
+	hiValue, err := strconv.Atoi(hi)
+	if err != nil {
+		return IntRange{}, fmt.Errorf("invalid upper bound '%s'", hi)
+	}
+	if loValue > hiValue {
+		return IntRange{}, fmt.Errorf(
+			"lower bound %d is greater than upper bound %d", loValue, hiValue,
+		)
+	}
+	return IntRange{Lo: loValue, Hi: hiValue}, nil
+}
+
+// requireIntEnv looks up an environment variable like requireEnv does and
+// parses it as base-10 integer. If the value cannot be parsed, an error is
+// logged and returned.
+func requireIntEnv(envName string) (int, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid integer", val, envName,
+		))
+	}
+	return n, nil
+}
+
+// parseIntEnv looks up an environment variable and parses it as base-10
+// integer. Unlike requireIntEnv, it does not log any error, so that callers
+// can aggregate the problems of several variables, but it emits the config
+// event of a missing or invalid value.
+func parseIntEnv(envName string) (int, error) {
+	val, found := lookupEnv(envName)
+	if len(val) == 0 {
+		state := envNotSet
+		if found {
+			state = envSetButEmpty
+		}
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		return 0, fmt.Errorf("please set the environment variable '%s', it %s", envName, state)
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		emitConfigEvent(envName, ConfigEventInvalid, nil)
+		return 0, fmt.Errorf("value '%s' for '%s' is not a valid integer", val, envName)
+	}
+	return n, nil
+}
@@ -0,0 +1,398 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	minVarName = "SOME_ARBITRARY_TEST_MIN_VAR_NAME"
+	maxVarName = "SOME_ARBITRARY_TEST_MAX_VAR_NAME"
+)
+
+func TestGetEnvIntRangePairOrFail_SucceedsIfOrdered(t *testing.T) {
+	t.Setenv(minVarName, "2")
+	t.Setenv(maxVarName, "2")
+
+	minValue, maxValue, err := GetEnvIntRangePairOrFail(minVarName, maxVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, minValue)
+	assert.Equal(t, 2, maxValue)
+}
+
+func TestGetEnvIntRangePairOrFail_FailsIfMinGreaterThanMax(t *testing.T) {
+	t.Setenv(minVarName, "8")
+	t.Setenv(maxVarName, "4")
+
+	_, _, err := GetEnvIntRangePairOrFail(minVarName, maxVarName)
+
+	assert.EqualError(t, err, "invalid range '"+minVarName+"'-'"+maxVarName+"': "+
+		"minimum 8 is greater than maximum 4")
+}
+
+func TestGetEnvIntRangePairOrFail_AggregatesProblemsOfBothVariables(t *testing.T) {
+	t.Setenv(minVarName, "")
+	t.Setenv(maxVarName, "many")
+
+	_, _, err := GetEnvIntRangePairOrFail(minVarName, maxVarName)
+
+	assert.EqualError(t, err, "invalid range '"+minVarName+"'-'"+maxVarName+"': "+
+		"please set the environment variable '"+minVarName+"', it is set but empty; "+
+		"value 'many' for '"+maxVarName+"' is not a valid integer")
+}
+
+func TestGetEnvIntOrFail_LogsWhetherEnvIsUnsetOrEmpty(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	_, err := GetEnvIntOrFail(envVarName)
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.Contains(t, buf.String(), "'"+envVarName+"', it is set but empty")
+
+	err = os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+	_, err = GetEnvIntOrFail(envVarName)
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+	assert.Contains(t, buf.String(), "'"+envVarName+"', it is not set")
+}
+
+func TestGetEnvEnabledIfPositiveOrFail_EnabledIfPositive(t *testing.T) {
+	t.Setenv(envVarName, "3")
+
+	enabled, value, err := GetEnvEnabledIfPositiveOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	assert.Equal(t, 3, value)
+}
+
+func TestGetEnvEnabledIfPositiveOrFail_DisabledIfZero(t *testing.T) {
+	t.Setenv(envVarName, "0")
+
+	enabled, value, err := GetEnvEnabledIfPositiveOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	assert.Equal(t, 0, value)
+}
+
+func TestGetEnvEnabledIfPositiveOrFail_FailsIfNegative(t *testing.T) {
+	t.Setenv(envVarName, "-1")
+
+	_, _, err := GetEnvEnabledIfPositiveOrFail(envVarName)
+
+	assert.EqualError(t, err, "value '-1' for '"+envVarName+"' must not be negative")
+}
+
+func TestGetEnvEnabledIfPositiveOrFail_FailsIfNotAnInteger(t *testing.T) {
+	t.Setenv(envVarName, "yes")
+
+	_, _, err := GetEnvEnabledIfPositiveOrFail(envVarName)
+
+	assert.EqualError(t, err, "value 'yes' for '"+envVarName+"' is not a valid integer")
+}
+
+func TestGetEnvEnabledIfPositiveOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, _, err := GetEnvEnabledIfPositiveOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+func TestGetEnvIntSatisfyingOrFail_SucceedsIfPredicateHolds(t *testing.T) {
+	t.Setenv(envVarName, "64")
+
+	actualValue, err := GetEnvIntSatisfyingOrFail(
+		envVarName, isPowerOfTwo, "must be a power of two",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 64, actualValue)
+}
+
+func TestGetEnvIntSatisfyingOrFail_FailsWithDescription(t *testing.T) {
+	t.Setenv(envVarName, "48")
+
+	_, err := GetEnvIntSatisfyingOrFail(envVarName, isPowerOfTwo, "must be a power of two")
+
+	assert.EqualError(t, err, "value '48' for '"+envVarName+"' must be a power of two")
+}
+
+func TestGetEnvIntSatisfyingOrFail_FailsIfNotAnInteger(t *testing.T) {
+	t.Setenv(envVarName, "4k")
+
+	_, err := GetEnvIntSatisfyingOrFail(envVarName, isPowerOfTwo, "must be a power of two")
+
+	assert.EqualError(t, err, "value '4k' for '"+envVarName+"' is not a valid integer")
+}
+
+func TestGetEnvIntOrDefaultStrictPresence_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "12")
+
+	actualValue, err := GetEnvIntOrDefaultStrictPresence(envVarName, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, actualValue)
+}
+
+func TestGetEnvIntOrDefaultStrictPresence_ReturnsDefaultIfMalformed(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "twelve")
+
+	actualValue, err := GetEnvIntOrDefaultStrictPresence(envVarName, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, actualValue)
+	assert.Contains(t, buf.String(), "level=warning")
+	assert.Contains(t, buf.String(),
+		"value 'twelve' for '"+envVarName+"' is not a valid integer, defaulting to 7")
+}
+
+func TestGetEnvIntOrDefaultStrictPresence_FailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	_, err = GetEnvIntOrDefaultStrictPresence(envVarName, 7)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvIntOrDefault_SucceedsIfSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "42")
+
+	actualValue := GetEnvIntOrDefault(envVarName, 7)
+
+	assert.Equal(t, 42, actualValue)
+	assert.Contains(t, buf.String(), "using configured value '42' for '"+envVarName+"'")
+}
+
+func TestGetEnvIntOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	actualValue := GetEnvIntOrDefault(envVarName, 7)
+
+	const expectedOutput = "environment variable '" + envVarName + "' is not set," +
+		" defaulting to 7"
+	assert.Equal(t, 7, actualValue)
+	assert.Contains(t, buf.String(), expectedOutput)
+}
+
+func TestGetEnvIntOrDefault_WarnsAndReturnsDefaultIfMalformed(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "4.2")
+
+	actualValue := GetEnvIntOrDefault(envVarName, 7)
+
+	assert.Equal(t, 7, actualValue)
+	assert.Contains(t, buf.String(), "level=warning")
+	assert.Contains(t, buf.String(),
+		"value '4.2' for '"+envVarName+"' is not a valid integer, defaulting to 7")
+}
+
+func TestGetEnvIntOrFail_SucceedsIfEnvSet(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "-42")
+
+	actualValue, err := GetEnvIntOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, -42, actualValue)
+	assert.Contains(t, buf.String(), "level=info msg=\"using configured value '-42' for '"+
+		envVarName+"'\"")
+}
+
+func TestGetEnvIntOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	_, err = GetEnvIntOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvIntOrFail_FailsIfNotAnInteger(t *testing.T) {
+	t.Setenv(envVarName, "0x10")
+
+	_, err := GetEnvIntOrFail(envVarName)
+
+	assert.EqualError(t, err, "value '0x10' for '"+envVarName+"' is not a valid integer")
+}
+
+func TestGetEnvIntBaseOrFail_ParsesWithBase(t *testing.T) {
+	tests := []struct {
+		val      string
+		base     int
+		expected int64
+	}{
+		{"ff", 16, 255},
+		{"0xFF", 0, 255},
+		{"0o17", 0, 15},
+		{"017", 0, 15},
+		{"0b101", 0, 5},
+		{"-42", 0, -42},
+		{"755", 8, 493},
+	}
+	for _, test := range tests {
+		t.Setenv(envVarName, test.val)
+
+		actualValue, err := GetEnvIntBaseOrFail(envVarName, test.base)
+
+		assert.NoError(t, err, test.val)
+		assert.Equal(t, test.expected, actualValue, test.val)
+	}
+}
+
+func TestGetEnvIntBaseOrFail_NamesDetectedBaseOnInvalidDigits(t *testing.T) {
+	tests := []struct {
+		val          string
+		base         int
+		expectedBase string
+	}{
+		{"0x1g", 0, "16"},
+		{"0o8", 0, "8"},
+		{"09", 0, "8"},
+		{"0b102", 0, "2"},
+		{"12a", 0, "10"},
+		{"8", 8, "8"},
+	}
+	for _, test := range tests {
+		t.Setenv(envVarName, test.val)
+
+		_, err := GetEnvIntBaseOrFail(envVarName, test.base)
+
+		assert.EqualError(t, err, "value '"+test.val+"' for '"+envVarName+
+			"' is not a valid integer in base "+test.expectedBase, test.val)
+	}
+}
+
+func TestGetEnvIntBaseOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvIntBaseOrFail(envVarName, 0)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvIntOrDerived_PrefersConfiguredValue(t *testing.T) {
+	t.Setenv(envVarName, "9090")
+
+	actualValue := GetEnvIntOrDerived(envVarName, func() (int, bool) { return 9080, true })
+
+	assert.Equal(t, 9090, actualValue)
+}
+
+func TestGetEnvIntOrDerived_DerivesIfNotSetOrInvalid(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(otherVarName, "8080")
+	derive := func() (int, bool) {
+		port, err := GetEnvIntOrFail(otherVarName)
+		return port + 1000, err == nil
+	}
+	for _, val := range []string{"", "metrics"} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvIntOrDerived(envVarName, derive)
+
+		assert.Equal(t, 9080, actualValue, val)
+	}
+	assert.Contains(t, buf.String(), "using derived value 9080")
+}
+
+func TestGetEnvIntOrDerived_ReturnsZeroIfDerivationFails(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvIntOrDerived(envVarName, func() (int, bool) { return 42, false })
+
+	assert.Equal(t, 0, actualValue)
+	assert.Contains(t, buf.String(), "could not derive a value for '"+envVarName+"'")
+}
+
+func TestGetEnvIntRangesOrFail_ParsesRangesAndNumbers(t *testing.T) {
+	t.Setenv(envVarName, "1-5, 10 ,,20 - 25,-3--1")
+
+	actualValue, err := GetEnvIntRangesOrFail(envVarName, ",")
+
+	assert.NoError(t, err)
+	assert.Equal(t, IntRanges{{1, 5}, {10, 10}, {20, 25}, {-3, -1}}, actualValue)
+	for _, n := range []int{1, 5, 10, 22, -2} {
+		assert.True(t, actualValue.Contains(n), n)
+	}
+	for _, n := range []int{0, 6, 11, 26, -4} {
+		assert.False(t, actualValue.Contains(n), n)
+	}
+}
+
+func TestGetEnvIntRangesOrFail_FailsOnMalformedOrInvertedRange(t *testing.T) {
+	tests := map[string]string{
+		"1-5,8-6": "element 1 '8-6' of '" + envVarName + "' is not a valid range: " +
+			"lower bound 8 is greater than upper bound 6",
+		"1-5,a-7": "element 1 'a-7' of '" + envVarName + "' is not a valid range: " +
+			"invalid lower bound 'a'",
+		"1-": "element 0 '1-' of '" + envVarName + "' is not a valid range: " +
+			"invalid upper bound ''",
+		"1-2-3": "element 0 '1-2-3' of '" + envVarName + "' is not a valid range: " +
+			"invalid upper bound '2-3'",
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvIntRangesOrFail(envVarName, ",")
+
+		assert.EqualError(t, err, expected, val)
+	}
+}
+
+func TestGetEnvIntRangesOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvIntRangesOrFail(envVarName, ",")
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// GetEnvEnumFromJSONOrFail looks up the environment variable valueVar and
+// validates it against the set of allowed values, which is read from the
+// environment variable allowedJSONVar as a JSON string array, e.g.
+// '["debug","info"]'. An error is returned if either variable is not set or
+// empty, if the allowed values are not a valid JSON string array or if the
+// value is not one of the allowed values.
+func GetEnvEnumFromJSONOrFail(valueVar, allowedJSONVar string) (string, error) {
+	allowedJSON, err := requireEnv(allowedJSONVar)
+	if err != nil {
+		return "", err
+	}
+	var allowed []string
+	if err := json.Unmarshal([]byte(allowedJSON), &allowed); err != nil {
+		return "", logEnvError(allowedJSONVar, fmt.Errorf(
+			"value of '%s' is not a valid JSON string array: %w", allowedJSONVar, err,
+		))
+	}
+	val, err := requireEnv(valueVar)
+	if err != nil {
+		return "", err
+	}
+	for _, candidate := range allowed {
+		if val == candidate {
+			logValue(valueVar, val)
+			return val, nil
+		}
+	}
+	return "", logEnvError(valueVar, fmt.Errorf(
+		"value '%s' for '%s' is not one of the values %q allowed by '%s'",
+		val,
+		valueVar,
+		allowed,
+		allowedJSONVar,
+	))
+}
+
+// EffectiveConfigJSON returns a JSON object mapping the provided names of
+// environment variables to their current values, e.g. for a diagnostics
+// endpoint. Variables that are not set map to null. Values of variables whose
+// name suggests a secret, e.g. because it contains "PASSWORD" or "TOKEN", are
+// replaced by "***". Keys are sorted, so the output is reproducible.
+func EffectiveConfigJSON(names []string) ([]byte, error) {
+	config := make(map[string]*string, len(names))
+	for _, name := range names {
+		val, found := lookupEnv(name)
+		if !found {
+			config[name] = nil
+			continue
+		}
+		if isSecretName(name) {
+			val = "***"
+		}
+		config[name] = &val
+	}
+	return json.Marshal(config)
+}
+
+// GetEnvJSONPathOrFail looks up an environment variable holding a JSON object
+// and extracts the value at the provided dotted path, e.g. "database.host".
+// Only scalar leaf values, i.e. strings, numbers and booleans, are supported
+// and returned in their string form. An error is returned if the variable is
+// not set or empty, if the JSON is malformed or followed by further data or
+// if the path does not lead to a scalar value.
+func GetEnvJSONPathOrFail(envName, jsonPath string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	decoder := json.NewDecoder(strings.NewReader(val))
+	decoder.UseNumber()
+	var current interface{}
+	if err := decoder.Decode(&current); err != nil {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value of '%s' is not valid JSON: %w", envName, err,
+		))
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value of '%s' contains data after the JSON value", envName,
+		))
+	}
+	for _, key := range strings.Split(jsonPath, ".") {
+		object, isObject := current.(map[string]interface{})
+		var found bool
+		if isObject {
+			current, found = object[key]
+		}
+		if !found {
+			return "", logEnvError(envName, fmt.Errorf(
+				"path '%s' is absent in value of '%s'", jsonPath, envName,
+			))
+		}
+	}
+	var result string
+	switch leaf := current.(type) {
+	case string:
+		result = leaf
+	case json.Number:
+		result = leaf.String()
+	case bool:
+		result = strconv.FormatBool(leaf)
+	default:
+		return "", logEnvError(envName, fmt.Errorf(
+			"path '%s' in value of '%s' is not a scalar value", jsonPath, envName,
+		))
+	}
+	logger.withEnv(envName).withValue(result).Infof(
+		"using configured value '%v' for '%v' of '%v'", result, jsonPath, envName,
+	)
+
+	return result, nil
+}
+
+// GetEnvJSONStrictOrFail looks up an environment variable holding a JSON
+// value and decodes it into a T. Unlike json.Unmarshal, keys that do not
+// match a field of T are rejected, so typos in structured configuration
+// surface instead of being silently dropped. An error is returned if the
+// variable is not set or empty, if the JSON is malformed, contains unknown
+// keys or is followed by further data.
+func GetEnvJSONStrictOrFail[T any](envName string) (T, error) {
+	var result T
+	val, err := requireEnv(envName)
+	if err != nil {
+		return result, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(val))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		var zero T
+		return zero, logEnvError(envName, fmt.Errorf(
+			"value of '%s' does not match the expected structure: %w", envName, err,
+		))
+	}
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		var zero T
+		return zero, logEnvError(envName, fmt.Errorf(
+			"value of '%s' contains data after the JSON value", envName,
+		))
+	}
+	logger.withEnv(envName).Infof("using configured JSON value for '%v'", envName)
+	emitConfigEvent(envName, ConfigEventOK, nil)
+
+	return result, nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const allowedVarName = "SOME_ARBITRARY_TEST_ALLOWED_VAR_NAME"
+
+func TestGetEnvEnumFromJSONOrFail_SucceedsIfAllowed(t *testing.T) {
+	t.Setenv(envVarName, "info")
+	t.Setenv(allowedVarName, `["debug", "info"]`)
+
+	actualValue, err := GetEnvEnumFromJSONOrFail(envVarName, allowedVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "info", actualValue)
+}
+
+func TestGetEnvEnumFromJSONOrFail_FailsIfNotAllowed(t *testing.T) {
+	t.Setenv(envVarName, "trace")
+	t.Setenv(allowedVarName, `["debug", "info"]`)
+
+	_, err := GetEnvEnumFromJSONOrFail(envVarName, allowedVarName)
+
+	assert.ErrorContains(t, err, "value 'trace' for '"+envVarName+"' is not one of the "+
+		`values ["debug" "info"] allowed by '`+allowedVarName+"'")
+}
+
+func TestGetEnvEnumFromJSONOrFail_FailsDistinctlyOnMalformedJSON(t *testing.T) {
+	t.Setenv(envVarName, "info")
+	t.Setenv(allowedVarName, `["debug", 1]`)
+
+	_, err := GetEnvEnumFromJSONOrFail(envVarName, allowedVarName)
+
+	assert.ErrorContains(t, err,
+		"value of '"+allowedVarName+"' is not a valid JSON string array")
+}
+
+func TestGetEnvEnumFromJSONOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	t.Setenv(allowedVarName, `["debug", "info"]`)
+
+	_, err := GetEnvEnumFromJSONOrFail(envVarName, allowedVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+const secretVarName = "SOME_ARBITRARY_TEST_PASSWORD"
+
+func TestEffectiveConfigJSON_MasksSecretsAndSortsKeys(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(secretVarName, "s3cr3t")
+	t.Setenv(allowedVarName, "")
+
+	actualValue, err := EffectiveConfigJSON([]string{envVarName, secretVarName, allowedVarName})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"`+allowedVarName+`":"",`+
+		`"`+envVarName+`":"Not Empty",`+
+		`"`+secretVarName+`":"***"}`, string(actualValue))
+}
+
+func TestEffectiveConfigJSON_ReportsUnsetVariablesAsNull(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	actualValue, err := EffectiveConfigJSON([]string{envVarName})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"`+envVarName+`":null}`, string(actualValue))
+}
+
+const jsonConfig = `{"database": {"host": "db.internal", "port": 5432, "tls": true,
+	"replicas": ["a"], "password": null}, "ratio": 0.25}`
+
+func TestGetEnvJSONPathOrFail_ExtractsScalarLeaves(t *testing.T) {
+	t.Setenv(envVarName, jsonConfig)
+	tests := map[string]string{
+		"database.host": "db.internal",
+		"database.port": "5432",
+		"database.tls":  "true",
+		"ratio":         "0.25",
+	}
+	for jsonPath, expected := range tests {
+		actualValue, err := GetEnvJSONPathOrFail(envVarName, jsonPath)
+
+		assert.NoError(t, err, jsonPath)
+		assert.Equal(t, expected, actualValue, jsonPath)
+	}
+}
+
+func TestGetEnvJSONPathOrFail_FailsIfPathAbsent(t *testing.T) {
+	t.Setenv(envVarName, jsonConfig)
+	for _, jsonPath := range []string{"database.user", "ratio.value", "cache"} {
+		_, err := GetEnvJSONPathOrFail(envVarName, jsonPath)
+
+		assert.EqualError(t, err,
+			"path '"+jsonPath+"' is absent in value of '"+envVarName+"'", jsonPath)
+	}
+}
+
+func TestGetEnvJSONPathOrFail_FailsIfNotScalar(t *testing.T) {
+	t.Setenv(envVarName, jsonConfig)
+	for _, jsonPath := range []string{"database", "database.replicas", "database.password"} {
+		_, err := GetEnvJSONPathOrFail(envVarName, jsonPath)
+
+		assert.EqualError(t, err,
+			"path '"+jsonPath+"' in value of '"+envVarName+"' is not a scalar value", jsonPath)
+	}
+}
+
+func TestGetEnvJSONPathOrFail_FailsIfMalformed(t *testing.T) {
+	t.Setenv(envVarName, `{"database": `)
+
+	_, err := GetEnvJSONPathOrFail(envVarName, "database")
+
+	assert.ErrorContains(t, err, "value of '"+envVarName+"' is not valid JSON")
+}
+
+func TestGetEnvJSONPathOrFail_FailsOnTrailingData(t *testing.T) {
+	t.Setenv(envVarName, `{"a":{"b":1}} trailing garbage`)
+
+	actualValue, err := GetEnvJSONPathOrFail(envVarName, "a.b")
+
+	assert.Empty(t, actualValue)
+	assert.EqualError(t, err, "value of '"+envVarName+"' contains data after the JSON value")
+}
+
+func TestGetEnvJSONPathOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvJSONPathOrFail(envVarName, "database")
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+type strictTestConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+func TestGetEnvJSONStrictOrFail_DecodesKnownFields(t *testing.T) {
+	t.Setenv(envVarName, `{"host": "db.internal", "port": 5432}`)
+
+	actualValue, err := GetEnvJSONStrictOrFail[strictTestConfig](envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, strictTestConfig{Host: "db.internal", Port: 5432}, actualValue)
+}
+
+func TestGetEnvJSONStrictOrFail_FailsOnUnknownField(t *testing.T) {
+	t.Setenv(envVarName, `{"host": "db.internal", "prot": 5432}`)
+
+	actualValue, err := GetEnvJSONStrictOrFail[strictTestConfig](envVarName)
+
+	assert.EqualError(t, err, "value of '"+envVarName+"' does not match the expected "+
+		`structure: json: unknown field "prot"`)
+	assert.Equal(t, strictTestConfig{}, actualValue)
+}
+
+func TestGetEnvJSONStrictOrFail_FailsOnTrailingData(t *testing.T) {
+	t.Setenv(envVarName, `{"host": "a"} {"host": "b"}`)
+
+	_, err := GetEnvJSONStrictOrFail[strictTestConfig](envVarName)
+
+	assert.EqualError(t, err, "value of '"+envVarName+"' contains data after the JSON value")
+}
+
+func TestGetEnvJSONStrictOrFail_FailsOnTrailingGarbage(t *testing.T) {
+	t.Setenv(envVarName, `{"host": "a"} }`)
+
+	_, err := GetEnvJSONStrictOrFail[strictTestConfig](envVarName)
+
+	assert.EqualError(t, err, "value of '"+envVarName+"' contains data after the JSON value")
+}
+
+func TestGetEnvJSONStrictOrFail_AcceptsTrailingWhitespace(t *testing.T) {
+	t.Setenv(envVarName, "{\"host\": \"a\"}\n ")
+
+	actualValue, err := GetEnvJSONStrictOrFail[strictTestConfig](envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a", actualValue.Host)
+}
+
+func TestGetEnvJSONStrictOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvJSONStrictOrFail[strictTestConfig](envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"sync"
+)
+
+// LazyValue is a value read from an environment variable and parsed on first
+// use. The environment variable is read and parsed exactly once, even when Get
+// is called concurrently, and the result is cached thereafter. Changes of the
+// environment at runtime are therefore ignored.
+type LazyValue[T any] struct {
+	envName      string
+	parse        func(string) (T, error)
+	defaultValue T
+	stringer     func(T) string
+
+	once  sync.Once
+	value T
+}
+
+// NewLazy creates a LazyValue for the environment variable with the provided
+// name. The value is parsed by the provided parse function. If the variable is
+// not set or cannot be parsed, the provided defaultValue is used instead.
+// Values are logged as rendered by fmt.Sprintf("%v", value), unless the
+// WithStringer option is provided.
+func NewLazy[T any](
+	envName string,
+	parse func(string) (T, error),
+	defaultValue T,
+	opts ...TypedOption[T],
+) *LazyValue[T] {
+	return &LazyValue[T]{
+		envName:      envName,
+		parse:        parse,
+		defaultValue: defaultValue,
+		stringer:     typedStringer(opts),
+	}
+}
+
+// Get returns the value, reading and parsing the environment variable on the
+// first call.
+func (l *LazyValue[T]) Get() T {
+	l.once.Do(func() {
+		l.value = l.load()
+	})
+	return l.value
+}
+
+func (l *LazyValue[T]) load() T {
+	return parseOrDefault(l.envName, getenv(l.envName), l.parse, l.defaultValue, l.stringer)
+}
+
+// MemoValue is a value read from an environment variable and parsed on use.
+// Unlike LazyValue, the environment variable is read on every call of Get,
+// but it is only parsed again if its value changed since the last call.
+// Repeated reads are thus cheap while changes of the environment at runtime
+// are still picked up.
+type MemoValue[T any] struct {
+	envName      string
+	parse        func(string) (T, error)
+	defaultValue T
+	stringer     func(T) string
+
+	mu     sync.Mutex
+	loaded bool
+	raw    string
+	value  T
+}
+
+// NewMemo creates a MemoValue for the environment variable with the provided
+// name. The value is parsed by the provided parse function. If the variable is
+// not set or cannot be parsed, the provided defaultValue is used instead.
+// Values are logged as rendered by fmt.Sprintf("%v", value), unless the
+// WithStringer option is provided.
+func NewMemo[T any](
+	envName string,
+	parse func(string) (T, error),
+	defaultValue T,
+	opts ...TypedOption[T],
+) *MemoValue[T] {
+	return &MemoValue[T]{
+		envName:      envName,
+		parse:        parse,
+		defaultValue: defaultValue,
+		stringer:     typedStringer(opts),
+	}
+}
+
+// Get returns the value, parsing the environment variable if it changed since
+// the last call. It is safe for concurrent use.
+func (m *MemoValue[T]) Get() T {
+	val := getenv(m.envName)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.loaded || val != m.raw {
+		m.value = parseOrDefault(m.envName, val, m.parse, m.defaultValue, m.stringer)
+		m.raw = val
+		m.loaded = true
+	}
+	return m.value
+}
+
+// parseOrDefault parses val, the value of the environment variable envName,
+// by parse. If val is empty or cannot be parsed, defaultValue is returned.
+// Values are logged as rendered by stringer.
+func parseOrDefault[T any](
+	envName, val string,
+	parse func(string) (T, error),
+	defaultValue T,
+	stringer func(T) string,
+) T {
+	if len(val) == 0 {
+		logDefault(envName, stringer(defaultValue))
+		return defaultValue
+	}
+	parsed, err := parse(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' cannot be parsed (%v), defaulting to %v",
+			val,
+			envName,
+			err,
+			stringer(defaultValue),
+		)
+		return defaultValue
+	}
+	logValue(envName, stringer(parsed))
+	return parsed
+}
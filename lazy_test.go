@@ -0,0 +1,134 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyValue_ParsesOnceAndIgnoresLaterChanges(t *testing.T) {
+	t.Setenv(envVarName, "42")
+	calls := 0
+	lazy := NewLazy(envVarName, func(val string) (int, error) {
+		calls++
+		return strconv.Atoi(val)
+	}, 7)
+
+	assert.Equal(t, 42, lazy.Get())
+	t.Setenv(envVarName, "43")
+	assert.Equal(t, 42, lazy.Get())
+	assert.Equal(t, 1, calls)
+}
+
+func TestLazyValue_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	lazy := NewLazy(envVarName, strconv.Atoi, 7)
+
+	assert.Equal(t, 7, lazy.Get())
+}
+
+func TestLazyValue_ReturnsDefaultIfParsingFails(t *testing.T) {
+	t.Setenv(envVarName, "forty-two")
+
+	lazy := NewLazy(envVarName, strconv.Atoi, 7)
+
+	assert.Equal(t, 7, lazy.Get())
+}
+
+func TestLazyValue_IsSafeForConcurrentUse(t *testing.T) {
+	t.Setenv(envVarName, "42")
+	lazy := NewLazy(envVarName, strconv.Atoi, 7)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 42, lazy.Get())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMemoValue_ParsesOnlyIfValueChanged(t *testing.T) {
+	t.Setenv(envVarName, "42")
+	calls := 0
+	memo := NewMemo(envVarName, func(val string) (int, error) {
+		calls++
+		return strconv.Atoi(val)
+	}, 7)
+
+	assert.Equal(t, 42, memo.Get())
+	assert.Equal(t, 42, memo.Get())
+	assert.Equal(t, 1, calls)
+
+	t.Setenv(envVarName, "43")
+	assert.Equal(t, 43, memo.Get())
+	assert.Equal(t, 43, memo.Get())
+	assert.Equal(t, 2, calls)
+}
+
+func TestMemoValue_ReturnsDefaultIfEnvNotSetOrParsingFails(t *testing.T) {
+	memo := NewMemo(envVarName, strconv.Atoi, 7)
+
+	t.Setenv(envVarName, "")
+	assert.Equal(t, 7, memo.Get())
+	t.Setenv(envVarName, "forty-two")
+	assert.Equal(t, 7, memo.Get())
+}
+
+func TestMemoValue_IsSafeForConcurrentUse(t *testing.T) {
+	t.Setenv(envVarName, "42")
+	memo := NewMemo(envVarName, strconv.Atoi, 7)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 42, memo.Get())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLazyAndMemoValue_LogValuesByStringer(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	parse := func(val string) (string, error) { return strings.ToUpper(val), nil }
+	mask := WithStringer(func(string) string { return "**********" })
+
+	t.Setenv(envVarName, "s3cr3t")
+	NewLazy(envVarName, parse, "default", mask).Get()
+	t.Setenv(envVarName, "")
+	NewMemo(envVarName, parse, "default", mask).Get()
+
+	assert.Contains(t, buf.String(), "using configured value '**********'")
+	assert.Contains(t, buf.String(), "defaulting to **********")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+	assert.NotContains(t, buf.String(), "default\"")
+}
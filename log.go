@@ -0,0 +1,250 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Logger receives the log messages of this package. The messages are already
+// formatted, so implementations only need to pass them on at the respective
+// level. Any *logrus.Logger or *logrus.Entry implements Logger, which is how
+// logrus.StandardLogger() serves as the default. SetLogger additionally
+// attaches structured fields to them, see FieldLogger. Other logging
+// libraries like slog can be plugged in via SetLogger with a small adapter.
+// Panic is only called when a getter is about to panic anyway, so it does not
+// need to panic itself.
+type Logger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+	Panic(args ...interface{})
+}
+
+// Keys of the structured fields attached to log messages, see FieldLogger.
+const (
+	// EnvVarField holds the name of the environment variable a message refers
+	// to.
+	EnvVarField = "env_var"
+	// ValueField holds the value used for the environment variable. It is
+	// never attached for secrets.
+	ValueField = "value"
+)
+
+// FieldLogger is a Logger which can attach structured fields to messages.
+// If the logger passed to SetLogger implements FieldLogger, messages about an
+// environment variable carry its name in the field EnvVarField, which allows
+// filtering logs by variable, and messages about a used value that is not a
+// secret carry the value in the field ValueField.
+type FieldLogger interface {
+	Logger
+	WithField(key string, value interface{}) Logger
+}
+
+// logRedaction replaces all matches of pattern in log messages.
+type logRedaction struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+var (
+	logRedactionsMu sync.RWMutex
+	logRedactions   []logRedaction
+)
+
+// RegisterLogRedaction registers a pattern whose matches are replaced in every
+// log message emitted by this package. The replacement may refer to submatches
+// like regexp.Regexp.ReplaceAllString does. This is a defense-in-depth measure
+// against leaking secrets embedded in otherwise loggable values, e.g. the
+// token in "https://host/path?token=abc" is redacted by registering
+// `(token=)[^&]*` with replacement "${1}**********".
+// Redactions are applied in registration order.
+func RegisterLogRedaction(pattern *regexp.Regexp, replacement string) {
+	logRedactionsMu.Lock()
+	defer logRedactionsMu.Unlock()
+	logRedactions = append(logRedactions, logRedaction{pattern, replacement})
+}
+
+// redact applies all registered redactions to msg.
+func redact(msg string) string {
+	logRedactionsMu.RLock()
+	defer logRedactionsMu.RUnlock()
+	for _, r := range logRedactions {
+		msg = r.pattern.ReplaceAllString(msg, r.replacement)
+	}
+	return msg
+}
+
+// LogLevel selects the level of configurable log messages.
+type LogLevel int
+
+// Levels of configurable log messages.
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// redactingLogger formats log messages, redacts them and passes them on to
+// the wrapped logger. It only provides the methods used by this package, so
+// no message can bypass the redaction.
+type redactingLogger struct {
+	logger Logger
+}
+
+// logrusLogger adapts a logrus logger to FieldLogger.
+type logrusLogger struct {
+	entry *logrus.Entry
+}
+
+func (l logrusLogger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+func (l logrusLogger) Info(args ...interface{})  { l.entry.Info(args...) }
+func (l logrusLogger) Warn(args ...interface{})  { l.entry.Warn(args...) }
+func (l logrusLogger) Error(args ...interface{}) { l.entry.Error(args...) }
+func (l logrusLogger) Panic(args ...interface{}) { l.entry.Panic(args...) }
+
+func (l logrusLogger) WithField(key string, value interface{}) Logger {
+	return logrusLogger{l.entry.WithField(key, value)}
+}
+
+// newRedactingLogger wraps newLogger, adapting logrus loggers to FieldLogger.
+func newRedactingLogger(newLogger Logger) redactingLogger {
+	switch l := newLogger.(type) {
+	case *logrus.Logger:
+		return redactingLogger{logrusLogger{logrus.NewEntry(l)}}
+	case *logrus.Entry:
+		return redactingLogger{logrusLogger{l}}
+	}
+	return redactingLogger{newLogger}
+}
+
+// logValue logs that val is used for the environment variable envName.
+func logValue(envName string, val interface{}) {
+	logValueAt(LogLevelInfo, envName, val)
+}
+
+// logValueAt logs like logValue, but at the provided level.
+func logValueAt(level LogLevel, envName string, val interface{}) {
+	logger.withEnv(envName).withValue(val).logf(
+		level, "using configured value '%v' for '%v'", val, envName,
+	)
+	emitConfigEvent(envName, ConfigEventOK, val)
+}
+
+// logDefault logs that defaultValue is used as the environment variable
+// envName has no value.
+func logDefault(envName string, defaultValue interface{}) {
+	logDefaultAt(LogLevelInfo, envName, defaultValue)
+}
+
+// logDefaultAt logs like logDefault, but at the provided level.
+func logDefaultAt(level LogLevel, envName string, defaultValue interface{}) {
+	logger.withEnv(envName).logf(
+		level,
+		"environment variable '%v' %v, defaulting to %v",
+		envName,
+		envState(envName),
+		defaultValue,
+	)
+	emitConfigEvent(envName, ConfigEventDefaulted, defaultValue)
+}
+
+// logInvalid logs a warning about the invalid value val of the environment
+// variable envName.
+func logInvalid(envName string, val interface{}, format string, args ...interface{}) {
+	logger.withEnv(envName).Warnf(format, args...)
+	emitConfigEvent(envName, ConfigEventInvalid, val)
+}
+
+// logEnvError logs err, which refers to the environment variable envName, and
+// returns it.
+func logEnvError(envName string, err error) error {
+	logger.withEnv(envName).Errorln(err)
+	emitConfigEvent(envName, ConfigEventInvalid, nil)
+	return err
+}
+
+// withEnv returns a logger attaching envName as field EnvVarField to all
+// messages, if the wrapped logger supports fields.
+func (l redactingLogger) withEnv(envName string) redactingLogger {
+	if fieldLogger, ok := l.logger.(FieldLogger); ok {
+		return redactingLogger{fieldLogger.WithField(EnvVarField, envName)}
+	}
+	return l
+}
+
+// withValue returns a logger attaching the redacted val as field ValueField to
+// all messages, if the wrapped logger supports fields.
+func (l redactingLogger) withValue(val interface{}) redactingLogger {
+	if fieldLogger, ok := l.logger.(FieldLogger); ok {
+		return redactingLogger{fieldLogger.WithField(ValueField, redact(fmt.Sprint(val)))}
+	}
+	return l
+}
+
+// logf logs at the provided level.
+func (l redactingLogger) logf(level LogLevel, format string, args ...interface{}) {
+	switch level {
+	case LogLevelDebug:
+		l.Debugf(format, args...)
+	case LogLevelWarn:
+		l.Warnf(format, args...)
+	case LogLevelError:
+		l.logger.Error(redact(fmt.Sprintf(format, args...)))
+	default:
+		l.Infof(format, args...)
+	}
+}
+
+func (l redactingLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactingLogger) Infof(format string, args ...interface{}) {
+	msg := redact(fmt.Sprintf(format, args...))
+	if bufferConfigSummary(msg) {
+		return
+	}
+	l.logger.Info(msg)
+}
+
+func (l redactingLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(redact(fmt.Sprintf(format, args...)))
+}
+
+func (l redactingLogger) Warnln(args ...interface{}) {
+	l.logger.Warn(redact(sprintln(args...)))
+}
+
+func (l redactingLogger) Errorln(args ...interface{}) {
+	l.logger.Error(redact(sprintln(args...)))
+}
+
+func (l redactingLogger) Panicln(args ...interface{}) {
+	l.logger.Panic(redact(sprintln(args...)))
+}
+
+// sprintln formats args like fmt.Sprintln, without the trailing newline.
+func sprintln(args ...interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// registerLogRedactionAndTearDown registers a log redaction and returns a
+// function removing all redactions again.
+func registerLogRedactionAndTearDown(pattern *regexp.Regexp, replacement string) func() {
+	RegisterLogRedaction(pattern, replacement)
+	return func() {
+		logRedactionsMu.Lock()
+		defer logRedactionsMu.Unlock()
+		logRedactions = nil
+	}
+}
+
+func TestRegisterLogRedaction_RedactsValueLogs(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	defer registerLogRedactionAndTearDown(regexp.MustCompile(`(token=)[^&']*`), "${1}***")()
+
+	t.Setenv(envVarName, "https://example.com/hook?token=abc123&mode=fast")
+
+	actualValue := GetEnvOrWarn(envVarName)
+
+	assert.Equal(t, "https://example.com/hook?token=abc123&mode=fast", actualValue)
+	assert.Contains(t, buf.String(), "https://example.com/hook?token=***&mode=fast")
+	assert.NotContains(t, buf.String(), "abc123")
+}
+
+func TestRegisterLogRedaction_RedactsWarningsAndErrors(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	defer registerLogRedactionAndTearDown(regexp.MustCompile(`abc\d+`), "[redacted]")()
+
+	t.Setenv(envVarName, "abc123")
+
+	_, err := GetEnvPortOrFail(envVarName, false)
+	GetEnvGlobOrDefault(envVarName+"_UNSET", "abc456")
+
+	assert.Error(t, err)
+	assert.Contains(t, buf.String(), "value '[redacted]' for")
+	assert.Contains(t, buf.String(), "defaulting to [redacted]")
+	assert.NotContains(t, buf.String(), "abc123")
+	assert.NotContains(t, buf.String(), "abc456")
+}
+
+func TestRegisterLogRedaction_AppliesRedactionsInOrder(t *testing.T) {
+	defer registerLogRedactionAndTearDown(regexp.MustCompile(`secret`), "hidden")()
+	RegisterLogRedaction(regexp.MustCompile(`hidden`), "gone")
+
+	assert.Equal(t, "gone value", redact("secret value"))
+}
+
+func TestRedactingLogger_PanicsWithRedactedMessage(t *testing.T) {
+	_, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	defer registerLogRedactionAndTearDown(regexp.MustCompile(`secret`), "***")()
+
+	assert.PanicsWithValue(t, "the *** is out", func() {
+		defer func() {
+			entry := recover().(*logrus.Entry)
+			panic(entry.Message)
+		}()
+		logger.Panicln("the", "secret", "is", "out")
+	})
+}
+
+// recordingLogger is a Logger recording all messages prefixed by their level.
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Debug(args ...interface{}) { l.record("debug", args) }
+func (l *recordingLogger) Info(args ...interface{})  { l.record("info", args) }
+func (l *recordingLogger) Warn(args ...interface{})  { l.record("warn", args) }
+func (l *recordingLogger) Error(args ...interface{}) { l.record("error", args) }
+func (l *recordingLogger) Panic(args ...interface{}) { l.record("panic", args) }
+
+func (l *recordingLogger) record(level string, args []interface{}) {
+	l.messages = append(l.messages, level+": "+fmt.Sprint(args...))
+}
+
+func TestSetLogger_AcceptsCustomLogger(t *testing.T) {
+	recorder := &recordingLogger{}
+	SetLogger(recorder)
+	defer SetLogger(logrus.StandardLogger())
+	defer registerLogRedactionAndTearDown(regexp.MustCompile(`Empty`), "***")()
+
+	t.Setenv(envVarName, expectedValue)
+	GetEnvOrWarn(envVarName)
+	t.Setenv(envVarName, "")
+	_, _ = GetEnvOrFail(envVarName)
+	assert.Panics(t, func() { GetEnvOrPanic(envVarName) })
+
+	assert.Equal(t, []string{
+		"info: using configured value 'Not ***' for '" + envVarName + "'",
+		"error: please set the environment variable '" + envVarName + "', it is set but empty",
+		"panic: please set the environment variable '" + envVarName + "', it is set but empty",
+	}, recorder.messages)
+}
+
+// setupJSONLoggingAndTearDown sets up a logrus logger writing JSON entries to
+// a temporary buffer and a teardown function restoring the default logger.
+func setupJSONLoggingAndTearDown() (*bytes.Buffer, func()) {
+	var buf bytes.Buffer
+	jsonLogger := logrus.New()
+	jsonLogger.SetOutput(&buf)
+	jsonLogger.SetFormatter(&logrus.JSONFormatter{})
+	SetLogger(jsonLogger)
+	return &buf, func() {
+		SetLogger(logrus.StandardLogger())
+	}
+}
+
+// decodeLogEntries decodes the JSON entries written to buf.
+func decodeLogEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		var entry map[string]interface{}
+		assert.NoError(t, decoder.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSetLogger_AttachesEnvVarAndValueFields(t *testing.T) {
+	buf, tearDownLogging := setupJSONLoggingAndTearDown()
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "8080")
+	GetEnvIntOrDefault(envVarName, 80)
+
+	entries := decodeLogEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, envVarName, entries[0][EnvVarField])
+	assert.Equal(t, "8080", entries[0][ValueField])
+}
+
+func TestSetLogger_AttachesOnlyEnvVarFieldIfMissing(t *testing.T) {
+	buf, tearDownLogging := setupJSONLoggingAndTearDown()
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+	_, _ = GetEnvDurationOrFail(envVarName)
+
+	entries := decodeLogEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "error", entries[0]["level"])
+	assert.Equal(t, envVarName, entries[0][EnvVarField])
+	assert.NotContains(t, entries[0], ValueField)
+}
+
+func TestSetLogger_DoesNotAttachValueFieldForSecrets(t *testing.T) {
+	buf, tearDownLogging := setupJSONLoggingAndTearDown()
+	defer tearDownLogging()
+
+	t.Setenv(secretVarName, "s3cr3t")
+	GetEnvSecretOrWarn(secretVarName)
+
+	entries := decodeLogEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, secretVarName, entries[0][EnvVarField])
+	assert.NotContains(t, entries[0], ValueField)
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestSetLogger_RedactsValueField(t *testing.T) {
+	buf, tearDownLogging := setupJSONLoggingAndTearDown()
+	defer tearDownLogging()
+	defer registerLogRedactionAndTearDown(regexp.MustCompile(`(token=)[^&]*`), "${1}***")()
+
+	t.Setenv(envVarName, "https://host/path?token=abc")
+	GetEnvOrDefault(envVarName, "")
+
+	entries := decodeLogEntries(t, buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "https://host/path?token=***", entries[0][ValueField])
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"strings"
+)
+
+// GetEnvMapOrDefault looks up an environment variable holding key value pairs
+// like "env=prod,region=eu,tier=1". Pairs are separated by "," and split into
+// key and value at the first "=". Keys and values are trimmed and empty pairs
+// are dropped. Pairs without "=" or with an empty key are skipped with a
+// warning. If a key occurs more than once, its last value wins. If the
+// variable is not set or contains no valid pair, a copy of the provided
+// defaultValue will be returned.
+func GetEnvMapOrDefault(envName string, defaultValue map[string]string) map[string]string {
+	elements := splitTrimmed(getenv(envName), ",")
+	result := make(map[string]string, len(elements))
+	for i, element := range elements {
+		key, value, found := strings.Cut(element, "=")
+		key = strings.TrimSpace(key)
+		if !found || len(key) == 0 {
+			logger.withEnv(envName).Warnf(
+				"pair %d '%v' of '%v' is not of the form 'key=value', skipping it",
+				i,
+				element,
+				envName,
+			)
+			continue
+		}
+		result[key] = strings.TrimSpace(value)
+	}
+	if len(result) == 0 {
+		logDefault(envName, defaultValue)
+		return copyMap(defaultValue)
+	}
+	logValue(envName, result)
+	return result
+}
+
+// copyMap returns a copy of m, so callers cannot modify m through it.
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
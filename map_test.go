@@ -0,0 +1,68 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvMapOrDefault_ParsesPairs(t *testing.T) {
+	t.Setenv(envVarName, "env=prod, region = eu,,tier=1,query=a=b")
+
+	actualValue := GetEnvMapOrDefault(envVarName, nil)
+
+	assert.Equal(t, map[string]string{
+		"env": "prod", "region": "eu", "tier": "1", "query": "a=b",
+	}, actualValue)
+}
+
+func TestGetEnvMapOrDefault_LastDuplicateWins(t *testing.T) {
+	t.Setenv(envVarName, "env=dev,env=prod")
+
+	actualValue := GetEnvMapOrDefault(envVarName, nil)
+
+	assert.Equal(t, map[string]string{"env": "prod"}, actualValue)
+}
+
+func TestGetEnvMapOrDefault_SkipsInvalidPairsWithWarning(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "env=prod,eu,=1")
+
+	actualValue := GetEnvMapOrDefault(envVarName, nil)
+
+	assert.Equal(t, map[string]string{"env": "prod"}, actualValue)
+	assert.Contains(t, buf.String(),
+		"pair 1 'eu' of '"+envVarName+"' is not of the form 'key=value', skipping it")
+	assert.Contains(t, buf.String(), "pair 2 '=1' of '"+envVarName+"'")
+}
+
+func TestGetEnvMapOrDefault_ReturnsCopyOfDefault(t *testing.T) {
+	defaultValue := map[string]string{"env": "dev"}
+	for _, val := range []string{"", "eu"} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvMapOrDefault(envVarName, defaultValue)
+		actualValue["env"] = "modified"
+
+		assert.Equal(t, map[string]string{"env": "dev"}, defaultValue, val)
+	}
+}
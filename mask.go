@@ -0,0 +1,131 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// AllowUnmaskedSecretsEnvName is the name of the environment variable which
+// must be set to "true" to allow disabling the masking of secrets.
+const AllowUnmaskedSecretsEnvName = "ENVTOOLS_ALLOW_UNMASKED_SECRETS"
+
+// secretMask replaces secrets in log messages by default and always in audit
+// entries and config events.
+const secretMask = "**********"
+
+var secretMaskingEnabled = true
+
+// secretMasker returns the replacement of a secret, see SetSecretMask.
+var secretMasker = defaultSecretMasker
+
+// defaultSecretMasker replaces every secret by secretMask, so the mask does
+// not reveal anything about the secret, not even its length.
+func defaultSecretMasker(string) string {
+	return secretMask
+}
+
+// SetSecretMask sets the function computing the replacement of a secret in
+// log messages, errors and other output, e.g. to always use eight "*" or to
+// keep the first two characters. The default replaces every secret by ten
+// "*". Passing nil restores the default. Masks revealing parts of secrets
+// should be chosen with care. Audit entries and config events are not
+// affected, they always hold ten "*" instead of a secret.
+func SetSecretMask(mask func(secret string) string) {
+	if mask == nil {
+		mask = defaultSecretMasker
+	}
+	secretMasker = mask
+}
+
+// SetSecretMaskingEnabled enables or disables the masking of secrets in log
+// messages. Masking is enabled by default and should only ever be disabled
+// to debug credential issues in non-production environments.
+// To prevent accidental exposure of secrets, disabling additionally requires
+// the environment variable AllowUnmaskedSecretsEnvName to be set to "true".
+// Otherwise, an error is returned and masking stays enabled.
+func SetSecretMaskingEnabled(enabled bool) error {
+	if enabled {
+		secretMaskingEnabled = true
+		return nil
+	}
+	if os.Getenv(AllowUnmaskedSecretsEnvName) != "true" {
+		return logError(fmt.Errorf(
+			"refusing to disable secret masking, set '%s' to 'true' to allow it",
+			AllowUnmaskedSecretsEnvName,
+		))
+	}
+	secretMaskingEnabled = false
+	logger.Warnln(
+		"SECRET MASKING IS DISABLED, secrets will be logged in plain text. " +
+			"Never do this in production!",
+	)
+	return nil
+}
+
+// maskSecret returns the mask for the secret val, or val itself if secret
+// masking is disabled.
+func maskSecret(val string) string {
+	if secretMaskingEnabled {
+		return secretMasker(val)
+	}
+	return val
+}
+
+// maskRevealingSuffix masks val but the last revealLast characters, revealing
+// at most half of them. If val is not longer than revealLast, it is masked
+// completely.
+func maskRevealingSuffix(val string, revealLast int) string {
+	runes := []rune(val)
+	if revealLast <= 0 || len(runes) <= revealLast {
+		return secretMasker(val)
+	}
+	if revealLast > len(runes)/2 {
+		revealLast = len(runes) / 2
+	}
+	return "******" + string(runes[len(runes)-revealLast:])
+}
+
+// logSecret logs that the secret val is used for the environment variable.
+// If secret masking is disabled, the value is logged with a warning.
+func logSecret(envName string, val string) {
+	emitConfigEvent(envName, ConfigEventOK, secretMask)
+	if secretMaskingEnabled {
+		logger.withEnv(envName).Infof(
+			"using configured secret '%v' for '%v'", secretMasker(val), envName,
+		)
+		return
+	}
+	logger.withEnv(envName).Warnf(
+		"using configured secret '%v' for '%v' (secret masking is disabled)",
+		val,
+		envName,
+	)
+}
+
+// secretNamePattern matches names of environment variables which likely hold
+// secrets.
+var secretNamePattern = regexp.MustCompile(
+	`(?i)(SECRET|PASSWORD|PASSWD|TOKEN|CREDENTIAL|API[_-]?KEY|PRIVATE[_-]?KEY)`,
+)
+
+// isSecretName reports whether the name of an environment variable suggests
+// that it holds a secret.
+func isSecretName(envName string) bool {
+	return secretNamePattern.MatchString(envName)
+}
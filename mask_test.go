@@ -0,0 +1,152 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretName_DetectsSecretNames(t *testing.T) {
+	for _, name := range []string{
+		"DB_PASSWORD", "client_secret", "GITHUB_TOKEN", "APIKEY", "STRIPE_API_KEY",
+		"TLS_PRIVATE_KEY", "AWS_CREDENTIALS", "X-Api-Key",
+	} {
+		assert.True(t, isSecretName(name), name)
+	}
+}
+
+func TestIsSecretName_IgnoresOtherNames(t *testing.T) {
+	for _, name := range []string{"HOME", "LOG_LEVEL", "DB_HOST", "KEYBOARD_LAYOUT"} {
+		assert.False(t, isSecretName(name), name)
+	}
+}
+
+func TestSetSecretMaskingEnabled_RefusesWithoutExplicitAllow(t *testing.T) {
+	t.Setenv(AllowUnmaskedSecretsEnvName, "")
+
+	err := SetSecretMaskingEnabled(false)
+
+	assert.ErrorContains(t, err, "refusing to disable secret masking, "+
+		"set '"+AllowUnmaskedSecretsEnvName+"' to 'true' to allow it")
+	assert.True(t, secretMaskingEnabled)
+}
+
+func TestSetSecretMaskingEnabled_LogsSecretsWhenDisabled(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(AllowUnmaskedSecretsEnvName, "true")
+	t.Setenv(envVarName, "s3cr3t")
+
+	err := SetSecretMaskingEnabled(false)
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, SetSecretMaskingEnabled(true))
+	}()
+	GetEnvSecretOrWarn(envVarName)
+
+	assert.Contains(t, buf.String(), "SECRET MASKING IS DISABLED")
+	assert.Contains(t, buf.String(), "level=warning msg=\"using configured secret 's3cr3t' for '"+
+		envVarName+"' (secret masking is disabled)\"")
+}
+
+func TestSetSecretMaskingEnabled_MasksAgainWhenReenabled(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(AllowUnmaskedSecretsEnvName, "true")
+	t.Setenv(envVarName, "s3cr3t")
+
+	assert.NoError(t, SetSecretMaskingEnabled(false))
+	assert.NoError(t, SetSecretMaskingEnabled(true))
+	GetEnvSecretOrWarn(envVarName)
+
+	assert.Contains(t, buf.String(), "using configured secret '**********'")
+	assert.NotContains(t, buf.String(), "'s3cr3t'")
+}
+
+func TestSetSecretMask_AppliesCustomMask(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	SetSecretMask(func(secret string) string {
+		return secret[:2] + "******"
+	})
+	defer SetSecretMask(nil)
+
+	t.Setenv(secretVarName, "s3cr3t")
+	GetEnvSecretOrWarn(secretVarName)
+	t.Setenv(envVarName, "postgres://app:s3cr3t@db:5432/app")
+	_, err := GetEnvConnStringOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "using configured secret 's3******'")
+	assert.Contains(t, buf.String(), "postgres://app:s3******@db:5432/app")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestSetSecretMask_DoesNotAffectAuditEntriesAndEvents(t *testing.T) {
+	events := recordConfigEventsAndTearDown(t)
+	SetSecretMask(func(secret string) string {
+		return secret[:2] + "******"
+	})
+	defer SetSecretMask(nil)
+
+	t.Setenv(secretVarName, "s3cr3t")
+	t.Setenv(envVarName, "s3cr3t")
+	audit := NewAuditGetter()
+	audit.GetEnvSecretOrWarn(envVarName)
+	GetEnvOrDefault(secretVarName, "")
+
+	assert.Equal(t, "**********", audit.AuditLog()[0].Value)
+	assert.Equal(t, []ConfigEvent{
+		{Name: envVarName, Category: ConfigEventOK, Value: "**********"},
+		{Name: secretVarName, Category: ConfigEventOK, Value: "**********"},
+	}, *events)
+}
+
+func TestSetSecretMask_NilRestoresDefault(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	SetSecretMask(func(string) string { return "********" })
+	SetSecretMask(nil)
+
+	t.Setenv(secretVarName, "s3cr3t")
+	GetEnvSecretOrWarn(secretVarName)
+
+	assert.Contains(t, buf.String(), "using configured secret '**********'")
+}
+
+func TestMaskRevealingSuffix(t *testing.T) {
+	tests := []struct {
+		val        string
+		revealLast int
+		expected   string
+	}{
+		{"ghp_abcdefcd12", 4, "******cd12"},
+		{"abcdef", 4, "******def"},
+		{"abcd", 4, "**********"},
+		{"ab", 4, "**********"},
+		{"abcdef", 0, "**********"},
+		{"pässwörter", 3, "******ter"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, maskRevealingSuffix(test.val, test.revealLast), test.val)
+	}
+}
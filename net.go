@@ -0,0 +1,330 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	minPort               = 1
+	maxPort               = 65535
+	firstUnprivilegedPort = 1024
+)
+
+// GetEnvPortOrFail looks up an environment variable and parses it as a TCP/UDP
+// port. An error is returned if the variable is not set or empty, if the value
+// is not a number, if it is outside of the range 1-65535 or, unless
+// allowPrivileged is set, if it is a privileged port below 1024.
+func GetEnvPortOrFail(envName string, allowPrivileged bool) (int, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	port, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a number", val, envName,
+		))
+	}
+	if port < minPort || port > maxPort {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"port %d for '%s' is out of range %d-%d", port, envName, minPort, maxPort,
+		))
+	}
+	if !allowPrivileged && port < firstUnprivilegedPort {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"privileged port %d for '%s' is not allowed, use a port of at least %d",
+			port,
+			envName,
+			firstUnprivilegedPort,
+		))
+	}
+	logValue(envName, port)
+
+	return port, nil
+}
+
+// GetEnvMACOrFail looks up an environment variable and parses it as a hardware
+// address using net.ParseMAC. If the environment variable is not set or empty,
+// or if the value is malformed, an error is returned.
+func GetEnvMACOrFail(envName string) (net.HardwareAddr, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := net.ParseMAC(val)
+	if err != nil {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid MAC address: %w", val, envName, err,
+		))
+	}
+	logValue(envName, mac)
+
+	return mac, nil
+}
+
+// GetEnvMACOrDefault looks up an environment variable and parses it as a
+// hardware address using net.ParseMAC. If the variable is not set or the value
+// is malformed, the provided defaultValue will be returned.
+func GetEnvMACOrDefault(envName string, defaultValue net.HardwareAddr) net.HardwareAddr {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	mac, err := net.ParseMAC(val)
+	if err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid MAC address, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, mac)
+	return mac
+}
+
+const (
+	maxHostnameLength = 253
+	maxLabelLength    = 63
+)
+
+// GetEnvHostnameOrFail looks up an environment variable and validates it as a
+// hostname according to RFC 1123: one or more dot separated labels of 1 to 63
+// letters, digits and hyphens, not starting or ending with a hyphen, and at
+// most 253 characters overall. IP addresses are rejected. If the environment
+// variable is not set or empty, or if the value violates a rule, an error
+// explaining the rule is returned.
+func GetEnvHostnameOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if problem := checkHostname(val); problem != "" {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid hostname: %s", val, envName, problem,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// checkHostname returns a description of the first RFC 1123 rule violated by
+// the hostname, or an empty string if it is valid.
+func checkHostname(hostname string) string {
+	if net.ParseIP(hostname) != nil {
+		return "it is an IP address"
+	}
+	if len(hostname) > maxHostnameLength {
+		return fmt.Sprintf("it is longer than %d characters", maxHostnameLength)
+	}
+	for i, label := range strings.Split(hostname, ".") {
+		switch {
+		case len(label) == 0:
+			return fmt.Sprintf("label %d is empty", i)
+		case len(label) > maxLabelLength:
+			return fmt.Sprintf("label %d is longer than %d characters", i, maxLabelLength)
+		case label[0] == '-' || label[len(label)-1] == '-':
+			return fmt.Sprintf("label %d '%s' starts or ends with a hyphen", i, label)
+		}
+		for _, c := range label {
+			if !isASCIIAlphanumeric(c) && c != '-' {
+				return fmt.Sprintf(
+					"label %d '%s' contains '%c', only letters, digits and hyphens are allowed",
+					i,
+					label,
+					c,
+				)
+			}
+		}
+	}
+	return ""
+}
+
+// isASCIIAlphanumeric reports whether c is an ASCII letter or digit.
+func isASCIIAlphanumeric(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// GetEnvGRPCTargetOrFail looks up an environment variable holding a gRPC
+// target. The accepted forms are "dns:[//authority/]host[:port]",
+// "unix:path" or "unix:///absolute/path", "passthrough:[//authority/]host:port"
+// and a plain "host:port". If the environment variable is not set or empty, or
+// if the value is malformed, an error explaining the accepted forms is
+// returned.
+func GetEnvGRPCTargetOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if problem := checkGRPCTarget(val); problem != "" {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid gRPC target: %s; expected one of "+
+				"'dns:///host:port', 'unix:///path', 'passthrough:///host:port' or 'host:port'",
+			val,
+			envName,
+			problem,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// checkGRPCTarget returns a description of the problem with the gRPC target,
+// or an empty string if it is valid.
+func checkGRPCTarget(target string) string {
+	scheme, rest, _ := strings.Cut(target, ":")
+	switch scheme {
+	case "unix":
+		if len(strings.TrimPrefix(rest, "//")) == 0 {
+			return "missing socket path"
+		}
+		return ""
+	case "dns", "passthrough":
+		endpoint := rest
+		if strings.HasPrefix(rest, "//") {
+			var found bool
+			if _, endpoint, found = strings.Cut(rest[2:], "/"); !found {
+				return "missing '/' after authority"
+			}
+		}
+		return checkHostPort(endpoint, scheme == "passthrough")
+	}
+	if strings.Contains(target, "://") {
+		return fmt.Sprintf("unsupported scheme '%s'", scheme)
+	}
+	return checkHostPort(target, true)
+}
+
+// checkHostPort returns a description of the problem with the endpoint
+// "host:port", or an empty string if it is valid.
+func checkHostPort(endpoint string, portRequired bool) string {
+	host, port, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		if portRequired || strings.Contains(endpoint, ":") {
+			return fmt.Sprintf("'%s' is not of the form host:port", endpoint)
+		}
+		host, port = endpoint, ""
+	}
+	if len(host) == 0 {
+		return "missing host"
+	}
+	if port == "" && !portRequired {
+		return ""
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < minPort || n > maxPort {
+		return fmt.Sprintf("invalid port '%s'", port)
+	}
+	return ""
+}
+
+// GetEnvIPOrFail looks up an environment variable and parses it as an IPv4 or
+// IPv6 address using net.ParseIP, e.g. for a bind address. If the environment
+// variable is not set or empty, or if the value is malformed, an error is
+// returned.
+func GetEnvIPOrFail(envName string) (net.IP, error) {
+	ip, err := requireIP(envName)
+	if err != nil {
+		return nil, err
+	}
+	logValue(envName, ip)
+
+	return ip, nil
+}
+
+// requireIP looks up an environment variable and parses it as IP address.
+// Unlike the getters, it does not log the parsed address.
+func requireIP(envName string) (net.IP, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(val)
+	if ip == nil {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid IP address", val, envName,
+		))
+	}
+	return ip, nil
+}
+
+// GetEnvCIDROrFail looks up an environment variable and parses it as an IP
+// network in CIDR notation like "10.0.0.0/8" using net.ParseCIDR. The returned
+// network is masked, so "10.1.2.3/8" results in "10.0.0.0/8". If the
+// environment variable is not set or empty, or if the value is malformed, an
+// error is returned.
+func GetEnvCIDROrFail(envName string) (*net.IPNet, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	_, ipNet, err := net.ParseCIDR(val)
+	if err != nil {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid CIDR: %w", val, envName, err,
+		))
+	}
+	logValue(envName, ipNet)
+
+	return ipNet, nil
+}
+
+// GetEnvIPInSubnetOrFail looks up an environment variable holding an IP
+// address and checks that it is contained in the provided subnet, e.g. a
+// private range, so that a service is not exposed outside of its intended
+// network. If the environment variable is not set or empty, if the value is
+// not a valid IP address or if it is outside of the subnet, an error is
+// returned.
+func GetEnvIPInSubnetOrFail(envName string, subnet *net.IPNet) (net.IP, error) {
+	ip, err := requireIP(envName)
+	if err != nil {
+		return nil, err
+	}
+	if !subnet.Contains(ip) {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"IP address '%s' for '%s' is not in the subnet '%s'", ip, envName, subnet,
+		))
+	}
+	logValue(envName, ip)
+
+	return ip, nil
+}
+
+// GetEnvIPInEnvSubnetOrFail looks up the subnet in CIDR notation, e.g.
+// "10.0.0.0/8", from the environment variable subnetEnvName and then the IP
+// address from the environment variable envName like GetEnvIPInSubnetOrFail.
+// An error is returned if the subnet is not set, empty or invalid as well.
+func GetEnvIPInEnvSubnetOrFail(envName, subnetEnvName string) (net.IP, error) {
+	val, err := requireEnv(subnetEnvName)
+	if err != nil {
+		return nil, err
+	}
+	_, subnet, err := net.ParseCIDR(val)
+	if err != nil {
+		return nil, logEnvError(subnetEnvName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid subnet: %w", val, subnetEnvName, err,
+		))
+	}
+	return GetEnvIPInSubnetOrFail(envName, subnet)
+}
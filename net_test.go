@@ -0,0 +1,313 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvPortOrFail_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "8080")
+
+	port, err := GetEnvPortOrFail(envVarName, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+}
+
+func TestGetEnvPortOrFail_FailsIfNotANumber(t *testing.T) {
+	t.Setenv(envVarName, "http")
+
+	_, err := GetEnvPortOrFail(envVarName, false)
+
+	assert.ErrorContains(t, err, "value 'http' for '"+envVarName+"' is not a number")
+}
+
+func TestGetEnvPortOrFail_FailsIfOutOfRange(t *testing.T) {
+	for _, val := range []string{"0", "65536", "-1"} {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvPortOrFail(envVarName, true)
+
+		assert.ErrorContains(t, err, "is out of range 1-65535", val)
+	}
+}
+
+func TestGetEnvPortOrFail_RejectsPrivilegedPortUnlessAllowed(t *testing.T) {
+	t.Setenv(envVarName, "443")
+
+	_, err := GetEnvPortOrFail(envVarName, false)
+	assert.ErrorContains(t, err, "privileged port 443 for '"+envVarName+"' is not allowed")
+
+	port, err := GetEnvPortOrFail(envVarName, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 443, port)
+}
+
+func TestGetEnvPortOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	_, err = GetEnvPortOrFail(envVarName, true)
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvMACOrFail_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "00:1A:2b:3c:4d:5e")
+
+	mac, err := GetEnvMACOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, mac)
+}
+
+func TestGetEnvMACOrFail_FailsIfMalformed(t *testing.T) {
+	t.Setenv(envVarName, "00:1a:2b")
+
+	_, err := GetEnvMACOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "value '00:1a:2b' for '"+envVarName+"' is not a valid MAC address")
+}
+
+func TestGetEnvMACOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvMACOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvMACOrDefault_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "00-1a-2b-3c-4d-5e")
+
+	mac := GetEnvMACOrDefault(envVarName, nil)
+
+	assert.Equal(t, net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, mac)
+}
+
+func TestGetEnvMACOrDefault_ReturnsDefaultIfMalformedOrNotSet(t *testing.T) {
+	defaultValue := net.HardwareAddr{0x02, 0, 0, 0, 0, 0x01}
+	for _, val := range []string{"", "not-a-mac"} {
+		t.Setenv(envVarName, val)
+
+		mac := GetEnvMACOrDefault(envVarName, defaultValue)
+
+		assert.Equal(t, defaultValue, mac, val)
+	}
+}
+
+func TestGetEnvHostnameOrFail_SucceedsIfValid(t *testing.T) {
+	for _, val := range []string{"localhost", "my-svc.default.svc.cluster.local", "1password.com"} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvHostnameOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, val, actualValue)
+	}
+}
+
+func TestGetEnvHostnameOrFail_ExplainsViolatedRule(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.1":              "it is an IP address",
+		"example.com.":          "label 2 is empty",
+		".example.com":          "label 0 is empty",
+		"-svc.example.com":      "label 0 '-svc' starts or ends with a hyphen",
+		"https://example.com":   "label 0 'https://example' contains ':'",
+		"my_svc":                "label 0 'my_svc' contains '_'",
+		strings.Repeat("a", 64): "label 0 is longer than 63 characters",
+	}
+	for val, expectedProblem := range tests {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvHostnameOrFail(envVarName)
+
+		assert.ErrorContains(t, err, "for '"+envVarName+"' is not a valid hostname: "+
+			expectedProblem, val)
+	}
+}
+
+func TestGetEnvHostnameOrFail_FailsIfTooLong(t *testing.T) {
+	t.Setenv(envVarName, strings.Repeat("abcdefghi.", 25)+"four")
+
+	_, err := GetEnvHostnameOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "it is longer than 253 characters")
+}
+
+func TestGetEnvHostnameOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvHostnameOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvGRPCTargetOrFail_AcceptsKnownForms(t *testing.T) {
+	for _, val := range []string{
+		"dns:///my-svc:50051",
+		"dns://8.8.8.8:53/my-svc:50051",
+		"dns:my-svc",
+		"unix:///var/run/grpc.sock",
+		"unix:relative.sock",
+		"passthrough:///10.0.0.1:50051",
+		"my-svc:50051",
+		"[::1]:50051",
+	} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvGRPCTargetOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, val, actualValue)
+	}
+}
+
+func TestGetEnvGRPCTargetOrFail_ExplainsProblem(t *testing.T) {
+	tests := map[string]string{
+		"my-svc":                 "'my-svc' is not of the form host:port",
+		"my-svc:grpc":            "invalid port 'grpc'",
+		"my-svc:70000":           "invalid port '70000'",
+		":50051":                 "missing host",
+		"unix:":                  "missing socket path",
+		"dns://8.8.8.8":          "missing '/' after authority",
+		"passthrough:///my-svc":  "'my-svc' is not of the form host:port",
+		"http://my-svc:50051":    "unsupported scheme 'http'",
+		"dns:///my-svc:50051:80": "'my-svc:50051:80' is not of the form host:port",
+	}
+	for val, expectedProblem := range tests {
+		t.Setenv(envVarName, val)
+
+		_, err := GetEnvGRPCTargetOrFail(envVarName)
+
+		assert.ErrorContains(t, err, "for '"+envVarName+"' is not a valid gRPC target: "+
+			expectedProblem+"; expected one of 'dns:///host:port'", val)
+	}
+}
+
+func TestGetEnvGRPCTargetOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvGRPCTargetOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvIPOrFail_ParsesIPv4AndIPv6(t *testing.T) {
+	for _, val := range []string{"192.168.1.1", "fd00::1"} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvIPOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, val, actualValue.String())
+	}
+}
+
+func TestGetEnvIPOrFail_FailsIfMalformed(t *testing.T) {
+	t.Setenv(envVarName, "10.0.0.256")
+
+	_, err := GetEnvIPOrFail(envVarName)
+
+	assert.EqualError(t, err, "value '10.0.0.256' for '"+envVarName+"' is not a valid IP address")
+}
+
+func TestGetEnvIPOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvIPOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvCIDROrFail_ReturnsMaskedNetwork(t *testing.T) {
+	t.Setenv(envVarName, "10.1.2.3/8")
+
+	actualValue, err := GetEnvCIDROrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", actualValue.String())
+}
+
+func TestGetEnvCIDROrFail_FailsIfMalformed(t *testing.T) {
+	t.Setenv(envVarName, "10.0.0.0/33")
+
+	_, err := GetEnvCIDROrFail(envVarName)
+
+	assert.ErrorContains(t, err, "value '10.0.0.0/33' for '"+envVarName+"' is not a valid CIDR")
+}
+
+func TestGetEnvCIDROrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvCIDROrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvIPInSubnetOrFail_SucceedsIfContained(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.0.0.0/8")
+	t.Setenv(envVarName, "10.1.2.3")
+
+	actualValue, err := GetEnvIPInSubnetOrFail(envVarName, subnet)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1.2.3", actualValue.String())
+}
+
+func TestGetEnvIPInSubnetOrFail_FailsIfOutsideSubnet(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.0.0.0/8")
+	t.Setenv(envVarName, "192.168.1.1")
+
+	_, err := GetEnvIPInSubnetOrFail(envVarName, subnet)
+
+	assert.EqualError(t, err,
+		"IP address '192.168.1.1' for '"+envVarName+"' is not in the subnet '10.0.0.0/8'")
+}
+
+func TestGetEnvIPInSubnetOrFail_FailsIfNotAnIP(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.0.0.0/8")
+	t.Setenv(envVarName, "10.0.0")
+
+	_, err := GetEnvIPInSubnetOrFail(envVarName, subnet)
+
+	assert.EqualError(t, err, "value '10.0.0' for '"+envVarName+"' is not a valid IP address")
+}
+
+func TestGetEnvIPInEnvSubnetOrFail_ReadsSubnetFromEnv(t *testing.T) {
+	t.Setenv(otherVarName, "fd00::/8")
+	t.Setenv(envVarName, "fd00::1")
+
+	actualValue, err := GetEnvIPInEnvSubnetOrFail(envVarName, otherVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "fd00::1", actualValue.String())
+}
+
+func TestGetEnvIPInEnvSubnetOrFail_FailsIfSubnetInvalid(t *testing.T) {
+	t.Setenv(otherVarName, "10.0.0.0")
+	t.Setenv(envVarName, "10.0.0.1")
+
+	_, err := GetEnvIPInEnvSubnetOrFail(envVarName, otherVarName)
+
+	assert.ErrorContains(t, err, "value '10.0.0.0' for '"+otherVarName+"' is not a valid subnet")
+}
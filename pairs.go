@@ -0,0 +1,68 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keyValuePair is a single "key=value" element of a list of pairs.
+type keyValuePair struct {
+	key   string
+	value string
+	raw   string
+}
+
+// requirePairs looks up an environment variable and splits it into pairs by
+// pairSep and each pair into key and value at the first kvSep. Keys and values
+// are trimmed, empty pairs are dropped. An error naming the position of the
+// offending pair is returned if a pair has no kvSep or an empty or duplicate
+// key. An error is returned as well if the variable is not set or empty or if
+// a separator is empty.
+func requirePairs(envName, pairSep, kvSep string) ([]keyValuePair, error) {
+	if len(pairSep) == 0 || len(kvSep) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf("empty separator for '%s'", envName))
+	}
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	elements := splitTrimmed(val, pairSep)
+	pairs := make([]keyValuePair, 0, len(elements))
+	seen := make(map[string]bool, len(elements))
+	for i, element := range elements {
+		key, value, found := strings.Cut(element, kvSep)
+		key = strings.TrimSpace(key)
+		switch {
+		case !found:
+			return nil, logEnvError(envName, fmt.Errorf(
+				"pair %d '%s' of '%s' is missing the separator '%s'", i, element, envName, kvSep,
+			))
+		case len(key) == 0:
+			return nil, logEnvError(envName, fmt.Errorf(
+				"pair %d '%s' of '%s' has an empty key", i, element, envName,
+			))
+		case seen[key]:
+			return nil, logEnvError(envName, fmt.Errorf(
+				"pair %d '%s' of '%s' repeats the key '%s'", i, element, envName, key,
+			))
+		}
+		seen[key] = true
+		pairs = append(pairs, keyValuePair{key: key, value: strings.TrimSpace(value), raw: element})
+	}
+	return pairs, nil
+}
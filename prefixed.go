@@ -0,0 +1,101 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import "time"
+
+// Prefixed looks up environment variables sharing a common prefix, so that
+// the prefix does not have to be repeated at every call site. The methods
+// take the name without prefix and behave like the getters of the same name,
+// e.g. GetOrFail like GetEnvOrFail. Log messages and errors state the full
+// name of the variable.
+type Prefixed struct {
+	prefix string
+}
+
+// NewPrefixed returns a Prefixed prepending the provided prefix to all names,
+// e.g. NewPrefixed("MYAPP_").GetOrFail("PORT") looks up "MYAPP_PORT". The
+// prefix is prepended as is, so it has to include a separator if desired.
+func NewPrefixed(prefix string) Prefixed {
+	return Prefixed{prefix: prefix}
+}
+
+// Name returns the full name of the environment variable with the provided
+// name without prefix.
+func (p Prefixed) Name(name string) string {
+	return p.prefix + name
+}
+
+// GetOrWarn behaves like GetEnvOrWarn for the prefixed name.
+func (p Prefixed) GetOrWarn(name string) string {
+	return GetEnvOrWarn(p.Name(name))
+}
+
+// GetOrDefault behaves like GetEnvOrDefault for the prefixed name.
+func (p Prefixed) GetOrDefault(name string, defaultValue string) string {
+	return GetEnvOrDefault(p.Name(name), defaultValue)
+}
+
+// GetOrFail behaves like GetEnvOrFail for the prefixed name.
+func (p Prefixed) GetOrFail(name string) (string, error) {
+	return GetEnvOrFail(p.Name(name))
+}
+
+// GetOrPanic behaves like GetEnvOrPanic for the prefixed name.
+func (p Prefixed) GetOrPanic(name string) string {
+	return GetEnvOrPanic(p.Name(name))
+}
+
+// GetSecretOrDefault behaves like GetEnvSecretOrDefault for the prefixed name.
+func (p Prefixed) GetSecretOrDefault(name string, defaultValue string) string {
+	return GetEnvSecretOrDefault(p.Name(name), defaultValue)
+}
+
+// GetSecretOrFail behaves like GetEnvSecretOrFail for the prefixed name.
+func (p Prefixed) GetSecretOrFail(name string) (string, error) {
+	return GetEnvSecretOrFail(p.Name(name))
+}
+
+// GetIntOrDefault behaves like GetEnvIntOrDefault for the prefixed name.
+func (p Prefixed) GetIntOrDefault(name string, defaultValue int) int {
+	return GetEnvIntOrDefault(p.Name(name), defaultValue)
+}
+
+// GetIntOrFail behaves like GetEnvIntOrFail for the prefixed name.
+func (p Prefixed) GetIntOrFail(name string) (int, error) {
+	return GetEnvIntOrFail(p.Name(name))
+}
+
+// GetBoolOrDefault behaves like GetEnvBoolOrDefault for the prefixed name.
+func (p Prefixed) GetBoolOrDefault(name string, defaultValue bool) bool {
+	return GetEnvBoolOrDefault(p.Name(name), defaultValue)
+}
+
+// GetBoolOrFail behaves like GetEnvBoolOrFail for the prefixed name.
+func (p Prefixed) GetBoolOrFail(name string) (bool, error) {
+	return GetEnvBoolOrFail(p.Name(name))
+}
+
+// GetDurationOrDefault behaves like GetEnvDurationOrDefault for the prefixed
+// name.
+func (p Prefixed) GetDurationOrDefault(name string, defaultValue time.Duration) time.Duration {
+	return GetEnvDurationOrDefault(p.Name(name), defaultValue)
+}
+
+// GetDurationOrFail behaves like GetEnvDurationOrFail for the prefixed name.
+func (p Prefixed) GetDurationOrFail(name string) (time.Duration, error) {
+	return GetEnvDurationOrFail(p.Name(name))
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// nolint: goconst
+package envtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const prefixedTestPrefix = "SOME_ARBITRARY_TEST_"
+
+func TestPrefixed_PrependsPrefix(t *testing.T) {
+	cfg := NewPrefixed(prefixedTestPrefix)
+	t.Setenv(prefixedTestPrefix+"PORT", "8080")
+	t.Setenv(prefixedTestPrefix+"TIMEOUT", "5s")
+	t.Setenv(prefixedTestPrefix+"VERBOSE", "true")
+	t.Setenv(prefixedTestPrefix+"HOST", "")
+
+	port, err := cfg.GetIntOrFail("PORT")
+	assert.NoError(t, err)
+	assert.Equal(t, 8080, port)
+
+	timeout, err := cfg.GetDurationOrFail("TIMEOUT")
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, timeout)
+
+	assert.True(t, cfg.GetBoolOrDefault("VERBOSE", false))
+	assert.Equal(t, "localhost", cfg.GetOrDefault("HOST", "localhost"))
+	assert.Equal(t, prefixedTestPrefix+"PORT", cfg.Name("PORT"))
+}
+
+func TestPrefixed_LogsAndFailsWithFullName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	cfg := NewPrefixed(prefixedTestPrefix)
+	t.Setenv(prefixedTestPrefix+"PORT", "8080")
+	t.Setenv(prefixedTestPrefix+"HOST", "")
+
+	_, err := cfg.GetOrFail("PORT")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(),
+		"using configured value '8080' for '"+prefixedTestPrefix+"PORT'")
+
+	_, err = cfg.GetOrFail("HOST")
+	assert.EqualError(t, err,
+		"please set the environment variable '"+prefixedTestPrefix+"HOST'")
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// quantitySuffixes maps the supported Kubernetes quantity suffixes to their
+// multipliers. Binary suffixes are listed first so that e.g. "Mi" is matched
+// before "M" would be considered.
+var quantitySuffixes = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"Pi", 1 << 50},
+	{"Ei", 1 << 60},
+	{"m", 1e-3},
+	{"k", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+	{"P", 1e15},
+	{"E", 1e18},
+}
+
+var quantityNumber = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// GetEnvQuantityOrFail looks up an environment variable and parses it as a
+// Kubernetes style resource quantity like "500m" or "1Gi".
+// The supported suffixes are the decimal suffixes m (10^-3), k (10^3),
+// M (10^6), G (10^9), T (10^12), P (10^15) and E (10^18) as well as the
+// binary suffixes Ki (2^10), Mi (2^20), Gi (2^30), Ti (2^40), Pi (2^50) and
+// Ei (2^60). A plain number without suffix is accepted, too. Exponent
+// notation like "1e3" is not supported.
+// The returned value is normalized, i.e. multiplied by the suffix's
+// multiplier, and the suffix is returned as given. An error is returned if
+// the variable is not set or empty or if the value cannot be parsed.
+func GetEnvQuantityOrFail(envName string) (value float64, suffix string, err error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, "", err
+	}
+	number, multiplier := val, 1.0
+	for _, s := range quantitySuffixes {
+		if strings.HasSuffix(val, s.suffix) {
+			number, suffix, multiplier = strings.TrimSuffix(val, s.suffix), s.suffix, s.multiplier
+			break
+		}
+	}
+	if !quantityNumber.MatchString(number) {
+		return 0, "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid quantity", val, envName,
+		))
+	}
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid quantity: %w", val, envName, err,
+		))
+	}
+	logValue(envName, val)
+
+	return parsed * multiplier, suffix, nil
+}
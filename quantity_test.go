@@ -0,0 +1,66 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvQuantityOrFail_ParsesSuffixes(t *testing.T) {
+	tests := []struct {
+		val            string
+		expectedValue  float64
+		expectedSuffix string
+	}{
+		{"500m", 0.5, "m"},
+		{"1Gi", 1 << 30, "Gi"},
+		{"1.5M", 1.5e6, "M"},
+		{"2k", 2000, "k"},
+		{"128Mi", 128 << 20, "Mi"},
+		{"3", 3, ""},
+		{".5", 0.5, ""},
+	}
+	for _, test := range tests {
+		t.Setenv(envVarName, test.val)
+
+		value, suffix, err := GetEnvQuantityOrFail(envVarName)
+
+		assert.NoError(t, err, test.val)
+		assert.InDelta(t, test.expectedValue, value, 1e-9, test.val)
+		assert.Equal(t, test.expectedSuffix, suffix, test.val)
+	}
+}
+
+func TestGetEnvQuantityOrFail_FailsOnUnrecognizedFormat(t *testing.T) {
+	for _, val := range []string{"1Xi", "Gi", "1 Gi", "1e3", "inf", "1GiB"} {
+		t.Setenv(envVarName, val)
+
+		_, _, err := GetEnvQuantityOrFail(envVarName)
+
+		assert.ErrorContains(t, err,
+			"value '"+val+"' for '"+envVarName+"' is not a valid quantity", val)
+	}
+}
+
+func TestGetEnvQuantityOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, _, err := GetEnvQuantityOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
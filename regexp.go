@@ -0,0 +1,142 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// GetEnvMatchingAnyOrFail looks up an environment variable and returns its
+// value if it fully matches at least one of the provided patterns, e.g. an IP
+// address or a hostname. Patterns are anchored implicitly, so they do not need
+// to start with "^" or end with "$". If the environment variable is not set or
+// empty, or if it matches none of the patterns, an error is returned.
+func GetEnvMatchingAnyOrFail(envName string, patterns ...*regexp.Regexp) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	sources := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if matchesFully(pattern, val) {
+			logValue(envName, val)
+			return val, nil
+		}
+		sources = append(sources, "'"+pattern.String()+"'")
+	}
+	return "", logEnvError(envName, fmt.Errorf(
+		"value '%s' for '%s' matches none of the patterns %s",
+		val,
+		envName,
+		strings.Join(sources, ", "),
+	))
+}
+
+// GetEnvFilteredOrFail looks up an environment variable and returns its value
+// if it fully matches at least one of the allow patterns and none of the deny
+// patterns, e.g. to accept redirect URLs of a domain except for some paths.
+// The deny patterns are evaluated first, so deny wins if a value matches
+// patterns of both lists. Patterns are anchored implicitly like for
+// GetEnvMatchingAnyOrFail. If the environment variable is not set or empty,
+// or if the value is rejected, an error naming the rejecting rule is returned.
+func GetEnvFilteredOrFail(envName string, allow, deny []*regexp.Regexp) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	for _, pattern := range deny {
+		if matchesFully(pattern, val) {
+			return "", logEnvError(envName, fmt.Errorf(
+				"value '%s' for '%s' is rejected by the deny pattern '%s'",
+				val,
+				envName,
+				pattern.String(),
+			))
+		}
+	}
+	sources := make([]string, 0, len(allow))
+	for _, pattern := range allow {
+		if matchesFully(pattern, val) {
+			logValue(envName, val)
+			return val, nil
+		}
+		sources = append(sources, "'"+pattern.String()+"'")
+	}
+	return "", logEnvError(envName, fmt.Errorf(
+		"value '%s' for '%s' matches none of the allow patterns %s",
+		val,
+		envName,
+		strings.Join(sources, ", "),
+	))
+}
+
+// GetEnvSafeRegexpOrFail looks up an environment variable holding a regular
+// expression and compiles it. To protect against pathological patterns from
+// less trusted configuration, the pattern is rejected if its compiled program
+// has more than maxProgramSize instructions, e.g. "a{1,500}" with a limit of
+// 100. If the environment variable is not set or empty, if the syntax is
+// invalid or if the pattern is too complex, an error is returned.
+func GetEnvSafeRegexpOrFail(envName string, maxProgramSize int) (*regexp.Regexp, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := regexp.Compile(val)
+	if err != nil {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' has invalid regular expression syntax: %w",
+			val,
+			envName,
+			err,
+		))
+	}
+	if size := programSize(val); size > maxProgramSize {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is too complex: its program has %d instructions, "+
+				"exceeding the maximum of %d",
+			val,
+			envName,
+			size,
+			maxProgramSize,
+		))
+	}
+	logValue(envName, val)
+
+	return pattern, nil
+}
+
+// programSize returns the number of instructions of the program compiled from
+// expr the way regexp.Compile does. expr must be a valid regular expression.
+func programSize(expr string) int {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return 0
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return 0
+	}
+	return len(prog.Inst)
+}
+
+// matchesFully reports whether the whole of val matches the pattern.
+func matchesFully(pattern *regexp.Regexp, val string) bool {
+	anchored := regexp.MustCompile(`^(?:` + pattern.String() + `)$`)
+	return anchored.MatchString(val)
+}
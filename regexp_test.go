@@ -0,0 +1,146 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ipPattern       = regexp.MustCompile(`\d{1,3}(\.\d{1,3}){3}`)
+	hostnamePattern = regexp.MustCompile(`[a-z0-9-]+(\.[a-z0-9-]+)*`)
+	redirectAllow   = []*regexp.Regexp{regexp.MustCompile(`https://app\.example\.com/.*`)}
+	redirectDeny    = []*regexp.Regexp{regexp.MustCompile(`.*/admin/.*`)}
+)
+
+func TestGetEnvMatchingAnyOrFail_SucceedsIfAnyPatternMatches(t *testing.T) {
+	for _, val := range []string{"10.0.0.1", "db.internal"} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvMatchingAnyOrFail(envVarName, ipPattern, hostnamePattern)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, val, actualValue)
+	}
+}
+
+func TestGetEnvMatchingAnyOrFail_RequiresFullMatch(t *testing.T) {
+	t.Setenv(envVarName, "10.0.0.1:8080")
+
+	_, err := GetEnvMatchingAnyOrFail(envVarName, ipPattern)
+
+	assert.Error(t, err)
+}
+
+func TestGetEnvMatchingAnyOrFail_AnchorsAlternations(t *testing.T) {
+	t.Setenv(envVarName, "ab")
+
+	actualValue, err := GetEnvMatchingAnyOrFail(envVarName, regexp.MustCompile(`a|ab`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", actualValue)
+}
+
+func TestGetEnvMatchingAnyOrFail_ListsPatternsIfNoneMatches(t *testing.T) {
+	t.Setenv(envVarName, "Not Empty")
+
+	_, err := GetEnvMatchingAnyOrFail(envVarName, ipPattern, hostnamePattern)
+
+	assert.EqualError(t, err, "value 'Not Empty' for '"+envVarName+"' matches none of "+
+		`the patterns '\d{1,3}(\.\d{1,3}){3}', '[a-z0-9-]+(\.[a-z0-9-]+)*'`)
+}
+
+func TestGetEnvMatchingAnyOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvMatchingAnyOrFail(envVarName, ipPattern)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvFilteredOrFail_SucceedsIfAllowedAndNotDenied(t *testing.T) {
+	t.Setenv(envVarName, "https://app.example.com/callback")
+
+	actualValue, err := GetEnvFilteredOrFail(envVarName, redirectAllow, redirectDeny)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://app.example.com/callback", actualValue)
+}
+
+func TestGetEnvFilteredOrFail_DenyWins(t *testing.T) {
+	t.Setenv(envVarName, "https://app.example.com/admin/login")
+
+	_, err := GetEnvFilteredOrFail(envVarName, redirectAllow, redirectDeny)
+
+	assert.EqualError(t, err, "value 'https://app.example.com/admin/login' for '"+envVarName+
+		"' is rejected by the deny pattern '.*/admin/.*'")
+}
+
+func TestGetEnvFilteredOrFail_FailsIfNotAllowed(t *testing.T) {
+	t.Setenv(envVarName, "https://evil.example.org/callback")
+
+	_, err := GetEnvFilteredOrFail(envVarName, redirectAllow, redirectDeny)
+
+	assert.EqualError(t, err, "value 'https://evil.example.org/callback' for '"+envVarName+
+		`' matches none of the allow patterns 'https://app\.example\.com/.*'`)
+}
+
+func TestGetEnvFilteredOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvFilteredOrFail(envVarName, redirectAllow, redirectDeny)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvSafeRegexpOrFail_CompilesSimplePattern(t *testing.T) {
+	t.Setenv(envVarName, `^/api/v\d+/`)
+
+	actualValue, err := GetEnvSafeRegexpOrFail(envVarName, 100)
+
+	assert.NoError(t, err)
+	assert.True(t, actualValue.MatchString("/api/v2/users"))
+}
+
+func TestGetEnvSafeRegexpOrFail_FailsOnInvalidSyntax(t *testing.T) {
+	t.Setenv(envVarName, "(unclosed")
+
+	_, err := GetEnvSafeRegexpOrFail(envVarName, 100)
+
+	assert.ErrorContains(t, err,
+		"value '(unclosed' for '"+envVarName+"' has invalid regular expression syntax")
+}
+
+func TestGetEnvSafeRegexpOrFail_FailsIfTooComplex(t *testing.T) {
+	t.Setenv(envVarName, "a{1,500}")
+
+	_, err := GetEnvSafeRegexpOrFail(envVarName, 100)
+
+	assert.ErrorContains(t, err, "value 'a{1,500}' for '"+envVarName+
+		"' is too complex: its program has")
+	assert.ErrorContains(t, err, "exceeding the maximum of 100")
+}
+
+func TestGetEnvSafeRegexpOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvSafeRegexpOrFail(envVarName, 100)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
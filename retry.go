@@ -0,0 +1,105 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of the settings of a RetryPolicy that are not configured.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBackoff     = time.Second
+	DefaultRetryMaxElapsed  = 30 * time.Second
+)
+
+// RetryPolicy bundles the common settings of retrying an operation, e.g. an
+// HTTP request.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Backoff is the delay before the first retry.
+	Backoff time.Duration
+	// MaxElapsed is the maximum time spent on all attempts.
+	MaxElapsed time.Duration
+}
+
+// GetRetryPolicyOrFail reads a RetryPolicy from the environment variables
+// <prefix>_MAX_ATTEMPTS, a positive integer, as well as <prefix>_BACKOFF and
+// <prefix>_MAX_ELAPSED, positive durations as understood by
+// time.ParseDuration. Variables that are not set default to
+// DefaultRetryMaxAttempts, DefaultRetryBackoff and DefaultRetryMaxElapsed
+// respectively, which is logged as a warning. If any value is invalid, an
+// error listing the problems of all variables is returned.
+func GetRetryPolicyOrFail(prefix string) (RetryPolicy, error) {
+	var problems []string
+	maxAttempts, problem := retryAttempts(prefix+"_MAX_ATTEMPTS", DefaultRetryMaxAttempts)
+	if problem != "" {
+		problems = append(problems, problem)
+	}
+	backoff, problem := retryDuration(prefix+"_BACKOFF", DefaultRetryBackoff)
+	if problem != "" {
+		problems = append(problems, problem)
+	}
+	maxElapsed, problem := retryDuration(prefix+"_MAX_ELAPSED", DefaultRetryMaxElapsed)
+	if problem != "" {
+		problems = append(problems, problem)
+	}
+	if len(problems) > 0 {
+		return RetryPolicy{}, logError(fmt.Errorf(
+			"invalid retry policy '%s': %s", prefix, strings.Join(problems, "; "),
+		))
+	}
+	policy := RetryPolicy{MaxAttempts: maxAttempts, Backoff: backoff, MaxElapsed: maxElapsed}
+	logger.Infof("using retry policy %+v for '%v'", policy, prefix)
+
+	return policy, nil
+}
+
+// retryAttempts reads a positive number of attempts from the environment
+// variable envName, defaulting to defaultValue with a warning if it is not
+// set. If the value is invalid, a description of the problem is returned.
+func retryAttempts(envName string, defaultValue int) (int, string) {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefaultAt(LogLevelWarn, envName, defaultValue)
+		return defaultValue, ""
+	}
+	attempts, err := strconv.Atoi(val)
+	if err != nil || attempts < 1 {
+		return 0, fmt.Sprintf("value '%s' for '%s' is not a positive integer", val, envName)
+	}
+	return attempts, ""
+}
+
+// retryDuration reads a positive duration from the environment variable
+// envName, defaulting to defaultValue with a warning if it is not set. If the
+// value is invalid, a description of the problem is returned.
+func retryDuration(envName string, defaultValue time.Duration) (time.Duration, string) {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefaultAt(LogLevelWarn, envName, defaultValue)
+		return defaultValue, ""
+	}
+	dur, err := time.ParseDuration(val)
+	if err != nil || dur <= 0 {
+		return 0, fmt.Sprintf("value '%s' for '%s' is not a positive duration", val, envName)
+	}
+	return dur, ""
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const retryPrefix = "SOME_ARBITRARY_TEST_RETRY"
+
+func TestGetRetryPolicyOrFail_ReadsAllSettings(t *testing.T) {
+	t.Setenv(retryPrefix+"_MAX_ATTEMPTS", "5")
+	t.Setenv(retryPrefix+"_BACKOFF", "250ms")
+	t.Setenv(retryPrefix+"_MAX_ELAPSED", "1m")
+
+	actualValue, err := GetRetryPolicyOrFail(retryPrefix)
+
+	assert.NoError(t, err)
+	assert.Equal(t, RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     250 * time.Millisecond,
+		MaxElapsed:  time.Minute,
+	}, actualValue)
+}
+
+func TestGetRetryPolicyOrFail_WarnsAndAppliesDefaults(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	t.Setenv(retryPrefix+"_MAX_ATTEMPTS", "")
+	t.Setenv(retryPrefix+"_BACKOFF", "")
+	t.Setenv(retryPrefix+"_MAX_ELAPSED", "10s")
+
+	actualValue, err := GetRetryPolicyOrFail(retryPrefix)
+
+	assert.NoError(t, err)
+	assert.Equal(t, RetryPolicy{
+		MaxAttempts: DefaultRetryMaxAttempts,
+		Backoff:     DefaultRetryBackoff,
+		MaxElapsed:  10 * time.Second,
+	}, actualValue)
+	assert.Contains(t, buf.String(),
+		"environment variable '"+retryPrefix+"_MAX_ATTEMPTS' is set but empty, defaulting to 3")
+	assert.Contains(t, buf.String(),
+		"environment variable '"+retryPrefix+"_BACKOFF' is set but empty, defaulting to 1s")
+}
+
+func TestGetRetryPolicyOrFail_AggregatesProblems(t *testing.T) {
+	t.Setenv(retryPrefix+"_MAX_ATTEMPTS", "0")
+	t.Setenv(retryPrefix+"_BACKOFF", "1s")
+	t.Setenv(retryPrefix+"_MAX_ELAPSED", "forever")
+
+	_, err := GetRetryPolicyOrFail(retryPrefix)
+
+	assert.EqualError(t, err, "invalid retry policy '"+retryPrefix+"': "+
+		"value '0' for '"+retryPrefix+"_MAX_ATTEMPTS' is not a positive integer; "+
+		"value 'forever' for '"+retryPrefix+"_MAX_ELAPSED' is not a positive duration")
+}
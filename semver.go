@@ -0,0 +1,184 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// semverOperators lists the supported comparison operators, longer ones
+// first so that ">=" is not taken for ">".
+var semverOperators = []string{">=", "<=", "!=", ">", "<", "=", "~", "^"}
+
+// semver holds the major, minor and patch number of a version.
+type semver [3]uint64
+
+// semverComparator compares a version against a fixed version.
+type semverComparator struct {
+	op      string
+	version semver
+}
+
+// GetEnvSemverConstraintOrFail looks up an environment variable holding a
+// semantic versioning constraint like ">=1.2.0 <2.0.0". A constraint consists
+// of alternatives separated by "||", each being a list of comparisons
+// separated by spaces or commas that all have to hold. A comparison is an
+// operator directly followed by a version "MAJOR.MINOR.PATCH", optionally
+// prefixed by "v". Supported operators are "=", "!=", ">", ">=", "<" and
+// "<=" as well as "~" (same minor version) and "^" (same major version, or
+// same minor version for 0.x). A version without operator must match
+// exactly. If the environment variable is not set or empty, or if the
+// constraint is malformed, an error naming the offending token is returned.
+// Use SatisfiesSemverConstraint to check a version against the constraint.
+func GetEnvSemverConstraintOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := parseSemverConstraint(val); err != nil {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid semver constraint: %w", val, envName, err,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// SatisfiesSemverConstraint reports whether version satisfies constraint, as
+// described for GetEnvSemverConstraintOrFail. An error is returned if the
+// version or the constraint is malformed.
+func SatisfiesSemverConstraint(version, constraint string) (bool, error) {
+	v, ok := parseSemver(version)
+	if !ok {
+		return false, fmt.Errorf("'%s' is not a valid semantic version", version)
+	}
+	alternatives, err := parseSemverConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is not a valid semver constraint: %w", constraint, err)
+	}
+	for _, comparators := range alternatives {
+		satisfied := true
+		for _, c := range comparators {
+			if !c.matches(v) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseSemverConstraint parses constraint into its alternatives, expanding
+// "~" and "^" into a pair of ">=" and "<" comparisons.
+func parseSemverConstraint(constraint string) ([][]semverComparator, error) {
+	var alternatives [][]semverComparator
+	for _, alternative := range strings.Split(constraint, "||") {
+		tokens := strings.FieldsFunc(alternative, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("empty alternative")
+		}
+		var comparators []semverComparator
+		for _, token := range tokens {
+			op := "="
+			for _, candidate := range semverOperators {
+				if strings.HasPrefix(token, candidate) {
+					op = candidate
+					break
+				}
+			}
+			v, ok := parseSemver(strings.TrimPrefix(token, op))
+			if !ok {
+				return nil, fmt.Errorf("invalid token '%s'", token)
+			}
+			switch op {
+			case "~":
+				comparators = append(comparators,
+					semverComparator{">=", v},
+					semverComparator{"<", semver{v[0], v[1] + 1, 0}},
+				)
+			case "^":
+				upper := semver{v[0] + 1, 0, 0}
+				if v[0] == 0 && v[1] == 0 {
+					upper = semver{0, 0, v[2] + 1}
+				} else if v[0] == 0 {
+					upper = semver{0, v[1] + 1, 0}
+				}
+				comparators = append(comparators,
+					semverComparator{">=", v},
+					semverComparator{"<", upper},
+				)
+			default:
+				comparators = append(comparators, semverComparator{op, v})
+			}
+		}
+		alternatives = append(alternatives, comparators)
+	}
+	return alternatives, nil
+}
+
+// parseSemver parses a version "MAJOR.MINOR.PATCH", optionally prefixed by
+// "v".
+func parseSemver(val string) (semver, bool) {
+	var v semver
+	parts := strings.Split(strings.TrimPrefix(val, "v"), ".")
+	if len(parts) != len(v) {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// matches reports whether v satisfies the comparison.
+func (c semverComparator) matches(v semver) bool {
+	cmp := 0
+	for i := range v {
+		if v[i] != c.version[i] {
+			cmp = 1
+			if v[i] < c.version[i] {
+				cmp = -1
+			}
+			break
+		}
+	}
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp == 0
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvSemverConstraintOrFail_SucceedsIfWellFormed(t *testing.T) {
+	for _, val := range []string{
+		">=1.2.0 <2.0.0",
+		"^1.2.3",
+		"~v0.4.1 || >=1.0.0, !=1.3.0",
+		"1.0.0",
+	} {
+		t.Setenv(envVarName, val)
+
+		actualValue, err := GetEnvSemverConstraintOrFail(envVarName)
+
+		assert.NoError(t, err, val)
+		assert.Equal(t, val, actualValue)
+	}
+}
+
+func TestGetEnvSemverConstraintOrFail_FailsNamingOffendingToken(t *testing.T) {
+	t.Setenv(envVarName, ">=1.2.0 <2.0")
+
+	_, err := GetEnvSemverConstraintOrFail(envVarName)
+
+	assert.EqualError(t, err, "value '>=1.2.0 <2.0' for '"+envVarName+
+		"' is not a valid semver constraint: invalid token '<2.0'")
+}
+
+func TestGetEnvSemverConstraintOrFail_FailsOnEmptyAlternative(t *testing.T) {
+	t.Setenv(envVarName, ">=1.2.0 ||")
+
+	_, err := GetEnvSemverConstraintOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "empty alternative")
+}
+
+func TestGetEnvSemverConstraintOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvSemverConstraintOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestSatisfiesSemverConstraint(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		expected   bool
+	}{
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"2.0.0", ">=1.2.0 <2.0.0", false},
+		{"1.9.9", "^1.2.3", true},
+		{"1.2.2", "^1.2.3", false},
+		{"0.3.0", "^0.2.3", false},
+		{"0.0.4", "^0.0.3", false},
+		{"0.4.9", "~0.4.1", true},
+		{"0.5.0", "~0.4.1", false},
+		{"v1.3.0", "~0.4.1 || >=1.0.0, !=1.3.0", false},
+		{"1.0.0", "1.0.0", true},
+		{"1.0.1", "=1.0.0", false},
+		{"1.0.0", "<=1.0.0", true},
+		{"1.0.0", ">1.0.0", false},
+	}
+	for _, test := range tests {
+		actualValue, err := SatisfiesSemverConstraint(test.version, test.constraint)
+
+		assert.NoError(t, err, test.constraint)
+		assert.Equal(t, test.expected, actualValue, test.version+" "+test.constraint)
+	}
+}
+
+func TestSatisfiesSemverConstraint_FailsOnMalformedVersion(t *testing.T) {
+	_, err := SatisfiesSemverConstraint("1.2", ">=1.0.0")
+
+	assert.EqualError(t, err, "'1.2' is not a valid semantic version")
+}
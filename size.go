@@ -0,0 +1,43 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"os"
+)
+
+// CheckEnvSize sums up the sizes of all environment variables of the process
+// and returns the total in bytes. Each variable accounts for its name, "=",
+// its value and a terminating NUL byte, which is how the environment is
+// passed to new processes. If the total exceeds maxBytes, e.g. the limit of
+// the platform, an error is returned along with the total. This helps to
+// diagnose "argument list too long" failures caused by oversized values.
+// Only sizes are logged, never values.
+func CheckEnvSize(maxBytes int) (int, error) {
+	total := 0
+	for _, entry := range os.Environ() {
+		total += len(entry) + 1
+	}
+	if total > maxBytes {
+		return total, logError(fmt.Errorf(
+			"environment size of %d bytes exceeds the maximum of %d bytes", total, maxBytes,
+		))
+	}
+	logger.Debugf("environment size of %d bytes is within the maximum of %d bytes", total, maxBytes)
+
+	return total, nil
+}
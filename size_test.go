@@ -0,0 +1,52 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckEnvSize_ReturnsTotal(t *testing.T) {
+	t.Setenv(envVarName, "")
+	before, err := CheckEnvSize(1 << 30)
+	assert.NoError(t, err)
+
+	t.Setenv(envVarName, "0123456789")
+	after, err := CheckEnvSize(1 << 30)
+
+	assert.NoError(t, err)
+	assert.Equal(t, before+10, after)
+}
+
+func TestCheckEnvSize_FailsIfTooLargeWithoutRevealingValues(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.DebugLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, strings.Repeat("x", 1024))
+
+	total, err := CheckEnvSize(1024)
+
+	assert.Greater(t, total, 1024)
+	assert.EqualError(t, err, "environment size of "+strconv.Itoa(total)+
+		" bytes exceeds the maximum of 1024 bytes")
+	assert.NotContains(t, buf.String(), "xxx")
+}
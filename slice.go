@@ -0,0 +1,285 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetEnvStringSliceOrDefault looks up an environment variable and splits its
+// value by ",", e.g. "a,b,c". Every element is trimmed and empty elements are
+// dropped, unless the KeepEmpty option is provided. If the variable is not set
+// or contains no elements, a copy of the provided defaultValue will be
+// returned.
+func GetEnvStringSliceOrDefault(
+	envName string,
+	defaultValue []string,
+	opts ...SliceOption,
+) []string {
+	return GetEnvStringSliceWithSep(envName, ",", defaultValue, opts...)
+}
+
+// SliceOption changes how GetEnvStringSliceOrDefault and
+// GetEnvStringSliceWithSep split a value.
+type SliceOption int
+
+const (
+	// KeepEmpty keeps empty elements instead of dropping them, e.g. for
+	// fixed-position lists like "a,,c" where the blank second element is
+	// meaningful. Elements are still trimmed, so "a, ,c" yields an empty
+	// second element as well.
+	KeepEmpty SliceOption = iota + 1
+)
+
+// GetEnvStringSliceWithSep looks up an environment variable and splits its
+// value by sep, e.g. for values containing commas themselves. Every element is
+// trimmed and empty elements are dropped, unless the KeepEmpty option is
+// provided. If the variable is not set or contains no elements, or if sep is
+// empty, a copy of the provided defaultValue will be returned.
+func GetEnvStringSliceWithSep(
+	envName, sep string,
+	defaultValue []string,
+	opts ...SliceOption,
+) []string {
+	if len(sep) == 0 {
+		logger.withEnv(envName).Warnf(
+			"empty separator for '%v', defaulting to %v", envName, defaultValue,
+		)
+		return copySlice(defaultValue)
+	}
+	logger.withEnv(envName).Debugf("splitting value of '%v' by separator '%v'", envName, sep)
+	val := getenv(envName)
+	var elements []string
+	if hasSliceOption(opts, KeepEmpty) {
+		if len(val) != 0 {
+			elements = strings.Split(val, sep)
+			for i, element := range elements {
+				elements[i] = strings.TrimSpace(element)
+			}
+		}
+	} else {
+		elements = splitTrimmed(val, sep)
+	}
+	if len(elements) == 0 {
+		logDefault(envName, defaultValue)
+		return copySlice(defaultValue)
+	}
+	logValue(envName, elements)
+	return elements
+}
+
+// hasSliceOption reports whether opts contains opt.
+func hasSliceOption(opts []SliceOption, opt SliceOption) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// GetEnvSliceValidatedOrFail looks up an environment variable and splits its
+// value by sep. Every element is trimmed, empty elements are dropped and the
+// remaining ones are checked by the provided validate function.
+// An error is returned if the variable is not set or empty, if no element is
+// left or if any element is invalid. In the latter case, the error lists all
+// invalid elements together with their index.
+func GetEnvSliceValidatedOrFail(
+	envName, sep string,
+	validate func(string) error,
+) ([]string, error) {
+	elements, err := requireSlice(envName, sep)
+	if err != nil {
+		return nil, err
+	}
+	var problems []string
+	for i, element := range elements {
+		if err := validate(element); err != nil {
+			problems = append(problems, fmt.Sprintf("[%d] '%s': %v", i, element, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"invalid elements in '%s': %s", envName, strings.Join(problems, "; "),
+		))
+	}
+	logValue(envName, elements)
+
+	return elements, nil
+}
+
+// GetEnvSliceStripPrefixOrDefault looks up an environment variable and splits
+// its value by sep. Every element is trimmed, empty elements are dropped and
+// the provided prefix is removed from the remaining ones, e.g. to strip a
+// common "arn:aws:s3:::" from a list of identifiers. Elements without the
+// prefix are kept as they are and a warning is logged for them.
+// If the variable is not set or contains no elements, a copy of the provided
+// defaultValue will be returned.
+func GetEnvSliceStripPrefixOrDefault(
+	envName, sep, prefix string,
+	defaultValue []string,
+) []string {
+	val := getenv(envName)
+	if len(sep) == 0 {
+		logger.withEnv(envName).Warnf(
+			"empty separator for '%v', defaulting to %v", envName, defaultValue,
+		)
+		return copySlice(defaultValue)
+	}
+	elements := splitTrimmed(val, sep)
+	if len(elements) == 0 {
+		logDefault(envName, defaultValue)
+		return copySlice(defaultValue)
+	}
+	for i, element := range elements {
+		if !strings.HasPrefix(element, prefix) {
+			logger.withEnv(envName).Warnf(
+				"element %d '%v' of '%v' lacks the expected prefix '%v', keeping it as is",
+				i,
+				element,
+				envName,
+				prefix,
+			)
+			continue
+		}
+		elements[i] = strings.TrimPrefix(element, prefix)
+	}
+	logValue(envName, elements)
+	return elements
+}
+
+// GetEnvSliceLenRangeOrFail looks up an environment variable and splits its
+// value by sep. Every element is trimmed and empty elements are dropped.
+// An error naming the actual count is returned unless the number of remaining
+// elements is between minLen and maxLen, both inclusive. An error is returned
+// as well if the variable is not set or empty.
+func GetEnvSliceLenRangeOrFail(envName, sep string, minLen, maxLen int) ([]string, error) {
+	if len(sep) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf("empty separator for '%s'", envName))
+	}
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	elements := splitTrimmed(val, sep)
+	if len(elements) < minLen || len(elements) > maxLen {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"'%s' has %d elements, expected between %d and %d",
+			envName,
+			len(elements),
+			minLen,
+			maxLen,
+		))
+	}
+	logValue(envName, elements)
+
+	return elements, nil
+}
+
+// GetEnvIntSliceOrFail looks up an environment variable and splits its value
+// by ",", e.g. "80,443,8080". Every element is trimmed, empty elements are
+// dropped and the remaining ones are parsed as base-10 integers. An error
+// naming the first invalid element and its index is returned if any element
+// cannot be parsed. An error is returned as well if the variable is not set
+// or empty or if no element is left.
+func GetEnvIntSliceOrFail(envName string) ([]int, error) {
+	elements, err := requireSlice(envName, ",")
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int, len(elements))
+	for i, element := range elements {
+		value, err := strconv.Atoi(element)
+		if err != nil {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"element %d '%s' of '%s' is not a valid integer", i, element, envName,
+			))
+		}
+		values[i] = value
+	}
+	logValue(envName, values)
+
+	return values, nil
+}
+
+// GetEnvUniqueSliceOrFail looks up an environment variable and splits its
+// value by sep. Every element is trimmed and empty elements are dropped.
+// An error naming the first repeated element is returned if any element
+// occurs more than once, e.g. for unique server IDs, where a duplicate
+// indicates a mistake. An error is returned as well if the variable is not
+// set or empty or if no element is left.
+func GetEnvUniqueSliceOrFail(envName, sep string) ([]string, error) {
+	elements, err := requireSlice(envName, sep)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		if seen[element] {
+			return nil, logEnvError(envName, fmt.Errorf(
+				"'%s' contains the element '%s' more than once", envName, element,
+			))
+		}
+		seen[element] = true
+	}
+	logValue(envName, elements)
+
+	return elements, nil
+}
+
+// requireSlice looks up an environment variable and splits it by sep.
+// An error is returned if the variable is not set or empty, if sep is empty
+// or if no non-empty element is left after splitting.
+func requireSlice(envName, sep string) ([]string, error) {
+	if len(sep) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf("empty separator for '%s'", envName))
+	}
+	val, err := requireEnv(envName)
+	if err != nil {
+		return nil, err
+	}
+	elements := splitTrimmed(val, sep)
+	if len(elements) == 0 {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' contains no elements", val, envName,
+		))
+	}
+	return elements, nil
+}
+
+// copySlice returns a copy of s, so callers cannot modify s through it.
+func copySlice(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	c := make([]string, len(s))
+	copy(c, s)
+	return c
+}
+
+// splitTrimmed splits val by sep, trims all elements and drops empty ones.
+func splitTrimmed(val, sep string) []string {
+	var elements []string
+	for _, element := range strings.Split(val, sep) {
+		element = strings.TrimSpace(element)
+		if len(element) != 0 {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
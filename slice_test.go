@@ -0,0 +1,263 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validateNoUnderscore(element string) error {
+	for _, c := range element {
+		if c == '_' {
+			return errors.New("must not contain '_'")
+		}
+	}
+	return nil
+}
+
+func TestGetEnvStringSliceOrDefault_SplitsAndTrims(t *testing.T) {
+	t.Setenv(envVarName, " a, b,,c ")
+
+	actualValue := GetEnvStringSliceOrDefault(envVarName, nil)
+
+	assert.Equal(t, []string{"a", "b", "c"}, actualValue)
+}
+
+func TestGetEnvStringSliceOrDefault_ReturnsCopyOfDefault(t *testing.T) {
+	defaultValue := []string{"localhost"}
+	for _, val := range []string{"", " , "} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvStringSliceOrDefault(envVarName, defaultValue)
+		actualValue[0] = "modified"
+
+		assert.Equal(t, []string{"localhost"}, defaultValue, val)
+	}
+}
+
+func TestGetEnvStringSliceWithSep_SplitsBySeparator(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.DebugLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, `C:\a,b; C:\c`)
+
+	actualValue := GetEnvStringSliceWithSep(envVarName, ";", nil)
+
+	assert.Equal(t, []string{`C:\a,b`, `C:\c`}, actualValue)
+	assert.Contains(t, buf.String(), "splitting value of '"+envVarName+"' by separator ';'")
+}
+
+func TestGetEnvStringSliceWithSep_WarnsAndReturnsCopyOfDefaultOnEmptySeparator(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.WarnLevel)
+	defer tearDownLogging()
+
+	defaultValue := []string{"localhost"}
+	t.Setenv(envVarName, "a;b")
+
+	actualValue := GetEnvStringSliceWithSep(envVarName, "", defaultValue)
+	actualValue[0] = "modified"
+
+	assert.Equal(t, []string{"localhost"}, defaultValue)
+	assert.Contains(t, buf.String(), "empty separator for '"+envVarName+"'")
+}
+
+func TestGetEnvStringSliceWithSep_KeepsEmptyElementsIfRequested(t *testing.T) {
+	t.Setenv(envVarName, "a,, c,")
+
+	assert.Equal(t, []string{"a", "c"}, GetEnvStringSliceWithSep(envVarName, ",", nil))
+	assert.Equal(t, []string{"a", "", "c", ""},
+		GetEnvStringSliceWithSep(envVarName, ",", nil, KeepEmpty))
+}
+
+func TestGetEnvStringSliceOrDefault_KeepsEmptyElementsIfRequested(t *testing.T) {
+	t.Setenv(envVarName, "a,, c")
+
+	assert.Equal(t, []string{"a", "", "c"}, GetEnvStringSliceOrDefault(envVarName, nil, KeepEmpty))
+}
+
+func TestGetEnvStringSliceWithSep_KeepEmptyReturnsDefaultIfNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvStringSliceWithSep(envVarName, ",", []string{"x"}, KeepEmpty)
+
+	assert.Equal(t, []string{"x"}, actualValue)
+}
+
+func TestGetEnvSliceValidatedOrFail_ReturnsCleanSlice(t *testing.T) {
+	t.Setenv(envVarName, " a.example.com, ,b.example.com ,")
+
+	actualValue, err := GetEnvSliceValidatedOrFail(envVarName, ",", validateNoUnderscore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, actualValue)
+}
+
+func TestGetEnvSliceValidatedOrFail_AggregatesErrorsWithIndices(t *testing.T) {
+	t.Setenv(envVarName, "a_1;ok;b_2")
+
+	_, err := GetEnvSliceValidatedOrFail(envVarName, ";", validateNoUnderscore)
+
+	assert.EqualError(t, err, "invalid elements in '"+envVarName+"': "+
+		"[0] 'a_1': must not contain '_'; [2] 'b_2': must not contain '_'")
+}
+
+func TestGetEnvSliceValidatedOrFail_FailsIfNoElements(t *testing.T) {
+	t.Setenv(envVarName, " , ")
+
+	_, err := GetEnvSliceValidatedOrFail(envVarName, ",", validateNoUnderscore)
+
+	assert.ErrorContains(t, err, "contains no elements")
+}
+
+func TestGetEnvSliceValidatedOrFail_FailsOnEmptySeparator(t *testing.T) {
+	t.Setenv(envVarName, "a,b")
+
+	_, err := GetEnvSliceValidatedOrFail(envVarName, "", validateNoUnderscore)
+
+	assert.ErrorContains(t, err, "empty separator for '"+envVarName+"'")
+}
+
+func TestGetEnvSliceValidatedOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvSliceValidatedOrFail(envVarName, ",", validateNoUnderscore)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+const arnPrefix = "arn:aws:s3:::"
+
+func TestGetEnvSliceStripPrefixOrDefault_StripsPrefix(t *testing.T) {
+	t.Setenv(envVarName, arnPrefix+"logs, "+arnPrefix+"backups")
+
+	actualValue := GetEnvSliceStripPrefixOrDefault(envVarName, ",", arnPrefix, nil)
+
+	assert.Equal(t, []string{"logs", "backups"}, actualValue)
+}
+
+func TestGetEnvSliceStripPrefixOrDefault_KeepsElementsWithoutPrefixWithWarning(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, arnPrefix+"logs,backups")
+
+	actualValue := GetEnvSliceStripPrefixOrDefault(envVarName, ",", arnPrefix, nil)
+
+	assert.Equal(t, []string{"logs", "backups"}, actualValue)
+	assert.Contains(t, buf.String(), "level=warning")
+	assert.Contains(t, buf.String(), "element 1 'backups' of '"+envVarName+
+		"' lacks the expected prefix '"+arnPrefix+"', keeping it as is")
+}
+
+func TestGetEnvSliceStripPrefixOrDefault_ReturnsCopyOfDefault(t *testing.T) {
+	defaultValue := []string{"logs"}
+	for _, sep := range []string{",", ""} {
+		t.Setenv(envVarName, " , ")
+
+		actualValue := GetEnvSliceStripPrefixOrDefault(envVarName, sep, arnPrefix, defaultValue)
+		actualValue[0] = "modified"
+
+		assert.Equal(t, []string{"logs"}, defaultValue, sep)
+	}
+}
+
+func TestGetEnvSliceLenRangeOrFail_SucceedsIfWithinRange(t *testing.T) {
+	t.Setenv(envVarName, "kafka-1:9092, kafka-2:9092,")
+
+	actualValue, err := GetEnvSliceLenRangeOrFail(envVarName, ",", 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, actualValue)
+}
+
+func TestGetEnvSliceLenRangeOrFail_CountsAfterDroppingEmptyElements(t *testing.T) {
+	t.Setenv(envVarName, " , ,")
+
+	_, err := GetEnvSliceLenRangeOrFail(envVarName, ",", 1, 10)
+
+	assert.EqualError(t, err, "'"+envVarName+"' has 0 elements, expected between 1 and 10")
+}
+
+func TestGetEnvSliceLenRangeOrFail_FailsIfTooLong(t *testing.T) {
+	t.Setenv(envVarName, "a b c")
+
+	_, err := GetEnvSliceLenRangeOrFail(envVarName, " ", 1, 2)
+
+	assert.EqualError(t, err, "'"+envVarName+"' has 3 elements, expected between 1 and 2")
+}
+
+func TestGetEnvSliceLenRangeOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvSliceLenRangeOrFail(envVarName, ",", 0, 2)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvUniqueSliceOrFail_SucceedsIfUnique(t *testing.T) {
+	t.Setenv(envVarName, "node-1, node-2, ,node-3")
+
+	actualValue, err := GetEnvUniqueSliceOrFail(envVarName, ",")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"node-1", "node-2", "node-3"}, actualValue)
+}
+
+func TestGetEnvUniqueSliceOrFail_FailsNamingDuplicate(t *testing.T) {
+	t.Setenv(envVarName, "node-1,node-2, node-1")
+
+	_, err := GetEnvUniqueSliceOrFail(envVarName, ",")
+
+	assert.EqualError(t, err, "'"+envVarName+"' contains the element 'node-1' more than once")
+}
+
+func TestGetEnvUniqueSliceOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvUniqueSliceOrFail(envVarName, ",")
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvIntSliceOrFail_ParsesElements(t *testing.T) {
+	t.Setenv(envVarName, "80, 443,,8080")
+
+	actualValue, err := GetEnvIntSliceOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{80, 443, 8080}, actualValue)
+}
+
+func TestGetEnvIntSliceOrFail_FailsNamingFirstInvalidElement(t *testing.T) {
+	t.Setenv(envVarName, "80,https,8o8o")
+
+	_, err := GetEnvIntSliceOrFail(envVarName)
+
+	assert.EqualError(t, err, "element 1 'https' of '"+envVarName+"' is not a valid integer")
+}
+
+func TestGetEnvIntSliceOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvIntSliceOrFail(envVarName)
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
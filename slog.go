@@ -0,0 +1,123 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package envtools
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// slogLogger passes the messages of this package on to a slog.Logger.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger for SetLogger which passes messages on to the
+// provided slog.Logger. Messages at panic level, which precede a panic of the
+// getter, are logged at error level. The name of the environment variable a
+// message refers to is attached as attribute EnvVarField.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger}
+}
+
+func (l slogLogger) Debug(args ...interface{}) {
+	l.logger.Debug(fmt.Sprint(args...))
+}
+
+func (l slogLogger) Info(args ...interface{}) {
+	l.logger.Info(fmt.Sprint(args...))
+}
+
+func (l slogLogger) Warn(args ...interface{}) {
+	l.logger.Warn(fmt.Sprint(args...))
+}
+
+func (l slogLogger) Error(args ...interface{}) {
+	l.logger.Error(fmt.Sprint(args...))
+}
+
+func (l slogLogger) Panic(args ...interface{}) {
+	l.logger.Error(fmt.Sprint(args...))
+}
+
+func (l slogLogger) WithField(key string, value interface{}) Logger {
+	return slogLogger{l.logger.With(key, value)}
+}
+
+// GetEnvSlogLevelOrDefault looks up an environment variable and parses it as
+// slog.Level. The names "debug", "info", "warn" and "error" are accepted
+// case-insensitively. If the variable is not set, the provided defaultValue
+// will be returned. If the name is unknown, a warning is logged and the
+// defaultValue will be returned as well.
+func GetEnvSlogLevelOrDefault(envName string, defaultValue slog.Level) slog.Level {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	level, ok := parseSlogLevel(val)
+	if !ok {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid level, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, level)
+	return level
+}
+
+// GetEnvSlogLevelOrFail looks up an environment variable and parses it as
+// slog.Level like GetEnvSlogLevelOrDefault does. If the environment variable
+// is not set or empty, or if the name is unknown, an error is returned.
+func GetEnvSlogLevelOrFail(envName string) (slog.Level, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return 0, err
+	}
+	level, ok := parseSlogLevel(val)
+	if !ok {
+		return 0, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid level, "+
+				"expected one of debug, info, warn or error",
+			val,
+			envName,
+		))
+	}
+	logValue(envName, level)
+
+	return level, nil
+}
+
+// parseSlogLevel parses the name of a slog.Level case-insensitively.
+func parseSlogLevel(val string) (slog.Level, bool) {
+	switch strings.ToLower(val) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	return 0, false
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package envtools
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvSlogLevelOrDefault_ParsesNamesCaseInsensitively(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"Warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+	for val, expected := range tests {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvSlogLevelOrDefault(envVarName, slog.LevelInfo)
+
+		assert.Equal(t, expected, actualValue, val)
+	}
+}
+
+func TestGetEnvSlogLevelOrDefault_WarnsAndReturnsDefaultOnUnknownName(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "verbose")
+
+	actualValue := GetEnvSlogLevelOrDefault(envVarName, slog.LevelWarn)
+
+	assert.Equal(t, slog.LevelWarn, actualValue)
+	assert.Contains(t, buf.String(), "level=warning")
+	assert.Contains(t, buf.String(),
+		"value 'verbose' for '"+envVarName+"' is not a valid level, defaulting to WARN")
+}
+
+func TestGetEnvSlogLevelOrDefault_ReturnsDefaultIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvSlogLevelOrDefault(envVarName, slog.LevelError)
+
+	assert.Equal(t, slog.LevelError, actualValue)
+}
+
+func TestGetEnvSlogLevelOrFail_SucceedsIfValid(t *testing.T) {
+	t.Setenv(envVarName, "DEBUG")
+
+	actualValue, err := GetEnvSlogLevelOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, actualValue)
+}
+
+func TestGetEnvSlogLevelOrFail_FailsOnUnknownName(t *testing.T) {
+	t.Setenv(envVarName, "verbose")
+
+	_, err := GetEnvSlogLevelOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "value 'verbose' for '"+envVarName+"' is not a valid level, "+
+		"expected one of debug, info, warn or error")
+}
+
+func TestGetEnvSlogLevelOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvSlogLevelOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+// setupSlogLoggingAndTearDown sets up a slog.Logger writing JSON records to a
+// temporary buffer and a teardown function restoring the default logger.
+func setupSlogLoggingAndTearDown() (*bytes.Buffer, func()) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	SetLogger(NewSlogLogger(slog.New(handler)))
+	return &buf, func() {
+		SetLogger(logrus.StandardLogger())
+	}
+}
+
+func TestNewSlogLogger_LogsInfoWithEnvVarAttribute(t *testing.T) {
+	buf, tearDownLogging := setupSlogLoggingAndTearDown()
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, expectedValue)
+
+	GetEnvOrDefault(envVarName, "Default Value")
+
+	records := decodeLogEntries(t, buf)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, envVarName, records[0][EnvVarField])
+	assert.Equal(t, "using configured value 'Not Empty' for '"+envVarName+"'", records[0]["msg"])
+}
+
+func TestNewSlogLogger_LogsWarningsAndFailuresWithEnvVarAttribute(t *testing.T) {
+	buf, tearDownLogging := setupSlogLoggingAndTearDown()
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	GetEnvOrWarn(envVarName)
+	_, _ = GetEnvOrFail(envVarName)
+	assert.Panics(t, func() { GetEnvOrPanic(envVarName) })
+
+	records := decodeLogEntries(t, buf)
+	assert.Len(t, records, 3)
+	for i, level := range []string{"WARN", "ERROR", "ERROR"} {
+		assert.Equal(t, level, records[i]["level"], i)
+		assert.Equal(t, envVarName, records[i][EnvVarField], i)
+	}
+}
+
+func TestNewSlogLogger_MasksSecrets(t *testing.T) {
+	buf, tearDownLogging := setupSlogLoggingAndTearDown()
+	defer tearDownLogging()
+
+	t.Setenv(secretVarName, "s3cr3t")
+
+	GetEnvSecretOrWarn(secretVarName)
+
+	assert.NotContains(t, buf.String(), "s3cr3t")
+	assert.Contains(t, buf.String(), `"`+EnvVarField+`":"`+secretVarName+`"`)
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Source is a source of configuration values, e.g. the environment or a
+// parsed config file.
+type Source interface {
+	// Name identifies the source in reports and log messages.
+	Name() string
+	// Lookup returns the value for key and whether the source has it.
+	Lookup(key string) (string, bool)
+}
+
+// EnvSource returns the Source for environment variables. Like the getters of
+// this package, it treats empty variables as not set and honours the aliases
+// registered with RegisterDeprecated.
+func EnvSource() Source {
+	return envSource{}
+}
+
+type envSource struct{}
+
+func (envSource) Name() string {
+	return "environment"
+}
+
+func (envSource) Lookup(key string) (string, bool) {
+	val := getenv(key)
+	return val, len(val) != 0
+}
+
+// NewMapSource returns a Source with the provided name serving the provided
+// values, e.g. the parsed content of a config file.
+func NewMapSource(name string, values map[string]string) Source {
+	return mapSource{name: name, values: values}
+}
+
+type mapSource struct {
+	name   string
+	values map[string]string
+}
+
+func (m mapSource) Name() string {
+	return m.name
+}
+
+func (m mapSource) Lookup(key string) (string, bool) {
+	val, found := m.values[key]
+	return val, found
+}
+
+// SourceReport states whether a single source had a key when resolving it.
+type SourceReport struct {
+	// Source is the name of the source.
+	Source string
+	// Found reports whether the source had the key.
+	Found bool
+	// Value is the value of the source, masked by "*" for secrets.
+	Value string
+}
+
+// ResolutionReport states where the value of a key came from.
+type ResolutionReport struct {
+	// Key is the resolved key.
+	Key string
+	// UsedSource is the name of the source providing the value. It is empty
+	// if no source had the key.
+	UsedSource string
+	// Sources lists all consulted sources in order of precedence.
+	Sources []SourceReport
+}
+
+// String formats the report for diagnostics.
+func (r ResolutionReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "'%s'", r.Key)
+	if r.UsedSource == "" {
+		b.WriteString(" is not set in any source")
+	} else {
+		fmt.Fprintf(&b, " is taken from %s", r.UsedSource)
+	}
+	for _, s := range r.Sources {
+		if s.Found {
+			fmt.Fprintf(&b, "; %s: '%s'", s.Source, s.Value)
+		} else {
+			fmt.Fprintf(&b, "; %s: not set", s.Source)
+		}
+	}
+	return b.String()
+}
+
+// ResolveWithReport looks up the key in all provided sources, which are given
+// in order of precedence, and returns the value of the first source having
+// it. The report lists for every source whether it had the key and which
+// value it had. Values of keys whose name suggests a secret, e.g. because it
+// contains "PASSWORD" or "TOKEN", are masked by "*" in the report and in the
+// log messages.
+func ResolveWithReport(key string, sources ...Source) (value string, report ResolutionReport) {
+	secret := isSecretName(key)
+	report.Key = key
+	report.Sources = make([]SourceReport, 0, len(sources))
+	for _, source := range sources {
+		val, found := source.Lookup(key)
+		sourceReport := SourceReport{Source: source.Name(), Found: found}
+		if found {
+			sourceReport.Value = val
+			if secret {
+				sourceReport.Value = maskSecret(val)
+			}
+			if report.UsedSource == "" {
+				report.UsedSource = source.Name()
+				value = val
+			}
+		}
+		report.Sources = append(report.Sources, sourceReport)
+	}
+	if report.UsedSource == "" {
+		logger.withEnv(key).Warnf("'%v' is not set in any source", key)
+		return "", report
+	}
+	if secret {
+		logSecret(key, value)
+	} else {
+		logValue(key, value)
+	}
+	logger.withEnv(key).Debugf("resolved %v", report)
+	return value, report
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveWithReport_UsesFirstSourceHavingKey(t *testing.T) {
+	t.Setenv(envVarName, "")
+	file := NewMapSource("config.yaml", map[string]string{envVarName: "from file"})
+	defaults := NewMapSource("defaults", map[string]string{envVarName: "default"})
+
+	value, report := ResolveWithReport(envVarName, EnvSource(), file, defaults)
+
+	assert.Equal(t, "from file", value)
+	assert.Equal(t, ResolutionReport{
+		Key:        envVarName,
+		UsedSource: "config.yaml",
+		Sources: []SourceReport{
+			{Source: "environment"},
+			{Source: "config.yaml", Found: true, Value: "from file"},
+			{Source: "defaults", Found: true, Value: "default"},
+		},
+	}, report)
+	assert.Equal(t, "'"+envVarName+"' is taken from config.yaml; environment: not set; "+
+		"config.yaml: 'from file'; defaults: 'default'", report.String())
+}
+
+func TestResolveWithReport_PrefersEnvironment(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	file := NewMapSource("config.yaml", map[string]string{envVarName: "from file"})
+
+	value, report := ResolveWithReport(envVarName, EnvSource(), file)
+
+	assert.Equal(t, expectedValue, value)
+	assert.Equal(t, "environment", report.UsedSource)
+}
+
+func TestResolveWithReport_MasksSecrets(t *testing.T) {
+	t.Setenv(secretVarName, "s3cr3t")
+	file := NewMapSource("config.yaml", map[string]string{secretVarName: "0ther"})
+
+	value, report := ResolveWithReport(secretVarName, EnvSource(), file)
+
+	assert.Equal(t, "s3cr3t", value)
+	assert.NotContains(t, report.String(), "s3cr3t")
+	assert.NotContains(t, report.String(), "0ther")
+	assert.Equal(t, "**********", report.Sources[1].Value)
+}
+
+func TestResolveWithReport_ReportsMissingKey(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	value, report := ResolveWithReport(envVarName, EnvSource())
+
+	assert.Equal(t, "", value)
+	assert.Equal(t, "'"+envVarName+"' is not set in any source; environment: not set",
+		report.String())
+}
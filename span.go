@@ -0,0 +1,83 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"context"
+)
+
+// SpanEventRecorder records events on a tracing span. It decouples this
+// package from a specific tracing library, an OpenTelemetry trace.Span can be
+// adapted by calling its AddEvent with attribute.String attributes.
+type SpanEventRecorder interface {
+	AddEvent(name string, attributes map[string]string)
+}
+
+// Name and attributes of the span event recorded by GetEnvCtx.
+const (
+	ConfigReadEventName   = "config.read"
+	ConfigKeyAttribute    = "config.key"
+	ConfigValueAttribute  = "config.value"
+	ConfigSourceAttribute = "config.source"
+)
+
+var spanFromContext func(ctx context.Context) SpanEventRecorder
+
+// SetSpanFromContext sets the function used by GetEnvCtx to get the span of a
+// context. It returns nil if the context has no (recording) span. Passing nil
+// disables the recording of span events.
+func SetSpanFromContext(f func(ctx context.Context) SpanEventRecorder) {
+	spanFromContext = f
+}
+
+// GetEnvCtx looks up the environment variable with the provided name like
+// GetEnvOrDefault does. If a span is present in ctx, see SetSpanFromContext,
+// an event noting the variable's name, value and source (environment or
+// default) is added to it. Values of variables whose name suggests a secret,
+// e.g. because it contains "PASSWORD" or "TOKEN", are masked by "*" in both
+// the log message and the event.
+func GetEnvCtx(ctx context.Context, envName string, defaultValue string) string {
+	secret := isSecretName(envName)
+	val, source := getenv(envName), "environment"
+	switch {
+	case len(val) == 0:
+		val, source = defaultValue, "default"
+		shown := defaultValue
+		if secret {
+			shown = maskSecret(defaultValue)
+		}
+		logDefault(envName, shown)
+	case secret:
+		logSecret(envName, val)
+	default:
+		logValue(envName, val)
+	}
+	if spanFromContext == nil {
+		return val
+	}
+	if span := spanFromContext(ctx); span != nil {
+		recorded := val
+		if secret {
+			recorded = secretMasker(val)
+		}
+		span.AddEvent(ConfigReadEventName, map[string]string{
+			ConfigKeyAttribute:    envName,
+			ConfigValueAttribute:  recorded,
+			ConfigSourceAttribute: source,
+		})
+	}
+	return val
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type spanEvent struct {
+	name       string
+	attributes map[string]string
+}
+
+type fakeSpan struct {
+	events []spanEvent
+}
+
+func (s *fakeSpan) AddEvent(name string, attributes map[string]string) {
+	s.events = append(s.events, spanEvent{name, attributes})
+}
+
+type spanKey struct{}
+
+// setupSpanAndTearDown returns a context holding a fake span and a teardown
+// function.
+func setupSpanAndTearDown() (context.Context, *fakeSpan, func()) {
+	span := &fakeSpan{}
+	SetSpanFromContext(func(ctx context.Context) SpanEventRecorder {
+		if span, ok := ctx.Value(spanKey{}).(*fakeSpan); ok {
+			return span
+		}
+		return nil
+	})
+	return context.WithValue(context.Background(), spanKey{}, span), span, func() {
+		SetSpanFromContext(nil)
+	}
+}
+
+func TestGetEnvCtx_AddsEventToSpan(t *testing.T) {
+	ctx, span, tearDownSpan := setupSpanAndTearDown()
+	defer tearDownSpan()
+
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue := GetEnvCtx(ctx, envVarName, "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Equal(t, []spanEvent{{ConfigReadEventName, map[string]string{
+		ConfigKeyAttribute:    envVarName,
+		ConfigValueAttribute:  expectedValue,
+		ConfigSourceAttribute: "environment",
+	}}}, span.events)
+}
+
+func TestGetEnvCtx_RecordsDefaultSource(t *testing.T) {
+	ctx, span, tearDownSpan := setupSpanAndTearDown()
+	defer tearDownSpan()
+
+	t.Setenv(envVarName, "")
+
+	actualValue := GetEnvCtx(ctx, envVarName, "Default Value")
+
+	assert.Equal(t, "Default Value", actualValue)
+	assert.Equal(t, "default", span.events[0].attributes[ConfigSourceAttribute])
+	assert.Equal(t, "Default Value", span.events[0].attributes[ConfigValueAttribute])
+}
+
+func TestGetEnvCtx_MasksSecrets(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+	ctx, span, tearDownSpan := setupSpanAndTearDown()
+	defer tearDownSpan()
+
+	t.Setenv(secretVarName, "s3cr3t")
+
+	actualValue := GetEnvCtx(ctx, secretVarName, "")
+
+	assert.Equal(t, "s3cr3t", actualValue)
+	assert.Equal(t, "**********", span.events[0].attributes[ConfigValueAttribute])
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestGetEnvCtx_WorksWithoutSpan(t *testing.T) {
+	_, span, tearDownSpan := setupSpanAndTearDown()
+	defer tearDownSpan()
+
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue := GetEnvCtx(context.Background(), envVarName, "Default Value")
+
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Empty(t, span.events)
+}
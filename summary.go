@@ -0,0 +1,65 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	configSummaryMu     sync.Mutex
+	configSummaryActive bool
+	configSummary       []string
+)
+
+// BeginConfig starts buffering the informational log messages of all lookups,
+// e.g. "using configured value 'x' for 'Y'", instead of logging each one.
+// EndConfig logs the buffered messages as a single summary, which keeps
+// startup logs tidy. Warnings and errors are still logged immediately.
+// Secrets are masked in the summary just like in the individual messages.
+func BeginConfig() {
+	configSummaryMu.Lock()
+	defer configSummaryMu.Unlock()
+	configSummaryActive = true
+}
+
+// EndConfig stops buffering started by BeginConfig and logs all messages
+// buffered since then as one summary at info level. Nothing is logged if no
+// message was buffered.
+func EndConfig() {
+	configSummaryMu.Lock()
+	lines := configSummary
+	configSummaryActive = false
+	configSummary = nil
+	configSummaryMu.Unlock()
+
+	if len(lines) > 0 {
+		logger.logger.Info("configuration summary:\n  " + strings.Join(lines, "\n  "))
+	}
+}
+
+// bufferConfigSummary adds msg to the summary if buffering was started by
+// BeginConfig. It returns false if msg has to be logged as usual.
+func bufferConfigSummary(msg string) bool {
+	configSummaryMu.Lock()
+	defer configSummaryMu.Unlock()
+	if !configSummaryActive {
+		return false
+	}
+	configSummary = append(configSummary, msg)
+	return true
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndConfig_LogsSingleSummary(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, expectedValue)
+	t.Setenv(secretVarName, "s3cr3t")
+	t.Setenv(otherVarName, "")
+
+	BeginConfig()
+	GetEnvOrDefault(envVarName, "Default Value")
+	GetEnvSecretOrWarn(secretVarName)
+	GetEnvOrDefault(otherVarName, "Default Value")
+	assert.Empty(t, buf.String())
+	EndConfig()
+
+	output := buf.String()
+	assert.Equal(t, 1, strings.Count(output, "level=info"))
+	assert.Contains(t, output, "configuration summary:")
+	assert.Contains(t, output, "using configured value 'Not Empty' for '"+envVarName+"'")
+	assert.Contains(t, output, "using configured secret '**********' for '"+secretVarName+"'")
+	assert.Contains(t, output, "environment variable '"+otherVarName+"' is set but empty")
+	assert.NotContains(t, output, "s3cr3t")
+}
+
+func TestBeginConfig_DoesNotBufferWarnings(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "")
+
+	BeginConfig()
+	defer EndConfig()
+	GetEnvOrWarn(envVarName)
+
+	assert.Contains(t, buf.String(), "environment variable '"+envVarName+"' is set but empty")
+}
+
+func TestEndConfig_LogsNothingIfNothingBuffered(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	BeginConfig()
+	EndConfig()
+	GetEnvOrDefault(envVarName, "Default Value")
+
+	assert.NotContains(t, buf.String(), "configuration summary")
+	assert.Contains(t, buf.String(), "level=info")
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// GetEnvTemplateOrFail looks up an environment variable and parses its value
+// as a text/template, e.g. "{{.HOME}}/cache/{{.USER}}". The template is
+// executed with a map of the current environment as data and the result is
+// returned. An error is returned if the variable is not set or empty, if the
+// template cannot be parsed or executed, or if it references an environment
+// variable that is not set.
+// If the template mentions a name suggesting a secret, e.g. because it
+// contains "PASSWORD" or "TOKEN", the result is masked by "*" in the log
+// message, as it likely contains the secret.
+func GetEnvTemplateOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(envName).Option("missingkey=error").Parse(val)
+	if err != nil {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value of '%s' is not a valid template: %w", envName, err,
+		))
+	}
+	var result strings.Builder
+	if err := tmpl.Execute(&result, environMap()); err != nil {
+		return "", logEnvError(envName, fmt.Errorf(
+			"failed to execute template of '%s': %w", envName, err,
+		))
+	}
+	if isSecretName(envName) || isSecretName(val) {
+		logSecret(envName, result.String())
+	} else {
+		logValue(envName, result.String())
+	}
+
+	return result.String(), nil
+}
+
+// environMap returns the current environment as a map from name to value.
+func environMap() map[string]string {
+	environ := os.Environ()
+	env := make(map[string]string, len(environ))
+	for _, entry := range environ {
+		name, val, _ := strings.Cut(entry, "=")
+		env[name] = val
+	}
+	return env
+}
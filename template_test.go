@@ -0,0 +1,89 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const templateVarName = "SOME_ARBITRARY_TEST_TEMPLATE_VAR_NAME"
+
+func TestGetEnvTemplateOrFail_ExecutesTemplateAgainstEnvironment(t *testing.T) {
+	t.Setenv(envVarName, "{{."+templateVarName+"}}/cache")
+	t.Setenv(templateVarName, "/home/test")
+
+	actualValue, err := GetEnvTemplateOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/home/test/cache", actualValue)
+}
+
+func TestGetEnvTemplateOrFail_MasksResultReferencingSecret(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "postgres://app:{{."+secretVarName+"}}@db/app")
+	t.Setenv(secretVarName, "hunter2")
+
+	actualValue, err := GetEnvTemplateOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://app:hunter2@db/app", actualValue)
+	assert.Contains(t, buf.String(), "using configured secret '**********'")
+	assert.NotContains(t, buf.String(), "hunter2")
+}
+
+func TestGetEnvTemplateOrFail_SupportsConditionals(t *testing.T) {
+	t.Setenv(envVarName, "{{if eq ."+templateVarName+" \"prod\"}}json{{else}}text{{end}}")
+	t.Setenv(templateVarName, "prod")
+
+	actualValue, err := GetEnvTemplateOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "json", actualValue)
+}
+
+func TestGetEnvTemplateOrFail_FailsOnParseError(t *testing.T) {
+	t.Setenv(envVarName, "{{.HOME")
+
+	_, err := GetEnvTemplateOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "value of '"+envVarName+"' is not a valid template")
+}
+
+func TestGetEnvTemplateOrFail_FailsOnMissingKey(t *testing.T) {
+	t.Setenv(envVarName, "{{."+templateVarName+"}}/cache")
+	t.Setenv(templateVarName, "")
+	err := os.Unsetenv(templateVarName)
+	assert.NoError(t, err)
+
+	_, err = GetEnvTemplateOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "failed to execute template of '"+envVarName+"'")
+}
+
+func TestGetEnvTemplateOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvTemplateOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
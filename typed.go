@@ -0,0 +1,76 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+)
+
+// TypedOption changes how the generic getters GetTypedE, NewLazy and NewMemo
+// log values of type T.
+type TypedOption[T any] struct {
+	stringer func(T) string
+}
+
+// WithStringer sets the function rendering values of type T in log messages
+// and config events, e.g. to log a meaningful representation of a complex type
+// or a mask for a secret. By default, values are rendered by
+// fmt.Sprintf("%v", value).
+func WithStringer[T any](stringer func(T) string) TypedOption[T] {
+	return TypedOption[T]{stringer: stringer}
+}
+
+// typedStringer returns the stringer set by the last WithStringer option or
+// the default rendering by fmt.Sprintf("%v", value).
+func typedStringer[T any](opts []TypedOption[T]) func(T) string {
+	for i := len(opts) - 1; i >= 0; i-- {
+		if opts[i].stringer != nil {
+			return opts[i].stringer
+		}
+	}
+	return func(value T) string {
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// GetTypedE looks up an environment variable and parses its value by parse.
+// Like os.LookupEnv, found tells whether the value came from the environment,
+// so a zero value parsed from e.g. "0" or "false" can be told apart from an
+// absent variable. An empty variable is treated as not set, so found is
+// false and err is nil in both cases. If the value cannot be parsed, found
+// is true and an error is returned. The parsed value is logged as rendered by
+// fmt.Sprintf("%v", value), unless the WithStringer option is provided.
+func GetTypedE[T comparable](
+	envName string,
+	parse func(string) (T, error),
+	opts ...TypedOption[T],
+) (value T, found bool, err error) {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logger.withEnv(envName).Infof("environment variable '%v' %v", envName, envState(envName))
+		emitConfigEvent(envName, ConfigEventMissing, nil)
+		return value, false, nil
+	}
+	parsed, err := parse(val)
+	if err != nil {
+		return value, true, logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' cannot be parsed: %w", val, envName, err,
+		))
+	}
+	logValue(envName, typedStringer(opts)(parsed))
+
+	return parsed, true, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTypedE_ReportsZeroValueFromEnvironmentAsFound(t *testing.T) {
+	t.Setenv(envVarName, "0")
+
+	actualValue, found, err := GetTypedE(envVarName, strconv.Atoi)
+
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, 0, actualValue)
+}
+
+func TestGetTypedE_ReportsNotFoundIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	actualValue, found, err := GetTypedE(envVarName, strconv.ParseBool)
+
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.False(t, actualValue)
+}
+
+func TestGetTypedE_FailsIfValueCannotBeParsed(t *testing.T) {
+	t.Setenv(envVarName, "maybe")
+
+	_, found, err := GetTypedE(envVarName, strconv.ParseBool)
+
+	assert.True(t, found)
+	assert.ErrorContains(t, err, "value 'maybe' for '"+envVarName+"' cannot be parsed")
+}
+
+func TestGetTypedE_LogsValueByStringer(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "1m30s")
+	_, _, err := GetTypedE(envVarName, time.ParseDuration)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "using configured value '1m30s'")
+
+	inSeconds := WithStringer(func(d time.Duration) string {
+		return strconv.Itoa(int(d.Seconds())) + " seconds"
+	})
+	_, _, err = GetTypedE(envVarName, time.ParseDuration, inSeconds)
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "using configured value '90 seconds'")
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GetEnvMaxLenOrFail looks up an environment variable. If the environment
+// variable is not set or empty, or if its value is longer than maxLen
+// characters, an error is returned.
+func GetEnvMaxLenOrFail(envName string, maxLen int) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkMaxLen(envName, val, maxLen); err != nil {
+		return "", err
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// GetEnvSecretMaxLenOrFail looks up an environment variable. If the environment
+// variable is not set or empty, or if its value is longer than maxLen
+// characters, an error is returned.
+// The difference to GetEnvMaxLenOrFail is that the extracted value is masked by "*".
+func GetEnvSecretMaxLenOrFail(envName string, maxLen int) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkMaxLen(envName, val, maxLen); err != nil {
+		return "", err
+	}
+	logSecret(envName, val)
+
+	return val, nil
+}
+
+// checkMaxLen returns an error if val is longer than maxLen characters.
+// The error only names the variable and the actual length, never the value.
+func checkMaxLen(envName string, val string, maxLen int) error {
+	length := utf8.RuneCountInString(val)
+	if length > maxLen {
+		return logEnvError(envName, fmt.Errorf(
+			"value of environment variable '%s' is %d characters long, "+
+				"exceeding the maximum of %d",
+			envName,
+			length,
+			maxLen,
+		))
+	}
+	return nil
+}
+
+// GetEnvASCIIOrFail looks up an environment variable. If the environment
+// variable is not set or empty, or if its value contains non-ASCII
+// characters, an error is returned.
+func GetEnvASCIIOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkASCII(envName, val); err != nil {
+		return "", err
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// GetEnvSecretASCIIOrFail looks up an environment variable. If the environment
+// variable is not set or empty, or if its value contains non-ASCII
+// characters, an error is returned.
+// The difference to GetEnvASCIIOrFail is that the extracted value is masked by "*".
+func GetEnvSecretASCIIOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkASCII(envName, val); err != nil {
+		return "", err
+	}
+	logSecret(envName, val)
+
+	return val, nil
+}
+
+// checkASCII returns an error naming the position of the first non-ASCII byte
+// in val. The error never contains the value itself.
+func checkASCII(envName string, val string) error {
+	for i := 0; i < len(val); i++ {
+		if val[i] > unicode.MaxASCII {
+			return logEnvError(envName, fmt.Errorf(
+				"value of environment variable '%s' contains a non-ASCII character "+
+					"at byte position %d",
+				envName,
+				i,
+			))
+		}
+	}
+	return nil
+}
+
+// GetEnvGlobOrFail looks up an environment variable holding a glob pattern as
+// understood by filepath.Match. If the environment variable is not set or
+// empty, or if the pattern is malformed, an error is returned.
+func GetEnvGlobOrFail(envName string) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := filepath.Match(val, ""); err != nil {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not a valid glob pattern: %w", val, envName, err,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// GetEnvGlobOrDefault looks up an environment variable holding a glob pattern
+// as understood by filepath.Match. If the variable is not set or the pattern
+// is malformed, the provided defaultValue will be returned.
+func GetEnvGlobOrDefault(envName string, defaultValue string) string {
+	val := getenv(envName)
+	if len(val) == 0 {
+		logDefault(envName, defaultValue)
+		return defaultValue
+	}
+	if _, err := filepath.Match(val, ""); err != nil {
+		logInvalid(envName, val,
+			"value '%v' for '%v' is not a valid glob pattern, defaulting to %v",
+			val,
+			envName,
+			defaultValue,
+		)
+		return defaultValue
+	}
+	logValue(envName, val)
+	return val
+}
+
+// GetEnvLowercaseOrFail looks up an environment variable. If the environment
+// variable is not set or empty, or if its value is not entirely lowercase, an
+// error is returned. The value is not normalized, so the operator is told to
+// fix the source instead.
+func GetEnvLowercaseOrFail(envName string) (string, error) {
+	return getEnvInCaseOrFail(envName, "lowercase", strings.ToLower)
+}
+
+// GetEnvUppercaseOrFail looks up an environment variable. If the environment
+// variable is not set or empty, or if its value is not entirely uppercase, an
+// error is returned. The value is not normalized, so the operator is told to
+// fix the source instead.
+func GetEnvUppercaseOrFail(envName string) (string, error) {
+	return getEnvInCaseOrFail(envName, "uppercase", strings.ToUpper)
+}
+
+// getEnvInCaseOrFail returns an error if the value of the environment
+// variable changes when converted by toCase.
+func getEnvInCaseOrFail(
+	envName string,
+	expectedCase string,
+	toCase func(string) string,
+) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	if toCase(val) != val {
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' must be entirely %s", val, envName, expectedCase,
+		))
+	}
+	logValue(envName, val)
+
+	return val, nil
+}
+
+// GetEnvValidBase64OrFail looks up an environment variable and checks that its
+// value is well-formed base64, using the URL safe alphabet if urlSafe is set
+// and the standard alphabet otherwise. Padding is required. The value is
+// returned as is, not decoded. If the environment variable is not set or
+// empty, or if the value is not valid base64, an error is returned.
+// For variables whose name suggests a secret, e.g. because it contains
+// "PASSWORD" or "TOKEN", the value is masked in errors and log messages.
+func GetEnvValidBase64OrFail(envName string, urlSafe bool) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.StdEncoding
+	if urlSafe {
+		encoding = base64.URLEncoding
+	}
+	secret := isSecretName(envName)
+	if _, err := encoding.DecodeString(val); err != nil {
+		shown := val
+		if secret {
+			shown = secretMasker(val)
+		}
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is not valid base64: %w", shown, envName, err,
+		))
+	}
+	if secret {
+		logSecret(envName, val)
+	} else {
+		logValue(envName, val)
+	}
+
+	return val, nil
+}
+
+// GetEnvPolicyCheckedOrFail looks up an environment variable and passes its
+// name and value to the provided check, e.g. to ask a central policy engine
+// whether the value is allowed. The check receives ctx, so that the call can
+// be cancelled or limited by a timeout. If the environment variable is not
+// set or empty, or if the check rejects the value by returning an error, an
+// error wrapping the one of the check is returned.
+// For variables whose name suggests a secret, e.g. because it contains
+// "PASSWORD" or "TOKEN", the value is masked in errors and log messages, but
+// passed unmasked to the check.
+func GetEnvPolicyCheckedOrFail(
+	ctx context.Context,
+	envName string,
+	check func(ctx context.Context, name, value string) error,
+) (string, error) {
+	val, err := requireEnv(envName)
+	if err != nil {
+		return "", err
+	}
+	secret := isSecretName(envName)
+	if err := check(ctx, envName, val); err != nil {
+		shown := val
+		if secret {
+			shown = secretMasker(val)
+		}
+		return "", logEnvError(envName, fmt.Errorf(
+			"value '%s' for '%s' is rejected by policy: %w", shown, envName, err,
+		))
+	}
+	if secret {
+		logSecret(envName, val)
+	} else {
+		logValue(envName, val)
+	}
+
+	return val, nil
+}
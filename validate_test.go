@@ -0,0 +1,325 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// nolint: goconst
+package envtools
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnvMaxLenOrFail_SucceedsIfWithinLimit(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue, err := GetEnvMaxLenOrFail(envVarName, len(expectedValue))
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvMaxLenOrFail_FailsIfTooLong(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	_, err := GetEnvMaxLenOrFail(envVarName, 3)
+
+	assert.ErrorContains(t, err,
+		"'"+envVarName+"' is 9 characters long, exceeding the maximum of 3")
+}
+
+func TestGetEnvMaxLenOrFail_CountsCharactersNotBytes(t *testing.T) {
+	t.Setenv(envVarName, "äöü")
+
+	actualValue, err := GetEnvMaxLenOrFail(envVarName, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "äöü", actualValue)
+}
+
+func TestGetEnvMaxLenOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+	err := os.Unsetenv(envVarName)
+	assert.NoError(t, err)
+
+	_, err = GetEnvMaxLenOrFail(envVarName, 3)
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvSecretMaxLenOrFail_DoesNotRevealValue(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "s3cr3t-value")
+
+	_, err := GetEnvSecretMaxLenOrFail(envVarName, 3)
+
+	assert.ErrorContains(t, err, "is 12 characters long")
+	assert.NotContains(t, err.Error(), "s3cr3t-value")
+	assert.NotContains(t, buf.String(), "s3cr3t-value")
+}
+
+func TestGetEnvSecretMaxLenOrFail_SucceedsIfWithinLimit(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "s3cr3t")
+
+	actualValue, err := GetEnvSecretMaxLenOrFail(envVarName, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", actualValue)
+	assert.Contains(t, buf.String(), "using configured secret '**********'")
+}
+
+func TestGetEnvASCIIOrFail_SucceedsIfASCII(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+
+	actualValue, err := GetEnvASCIIOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedValue, actualValue)
+}
+
+func TestGetEnvASCIIOrFail_FailsOnFirstNonASCIIPosition(t *testing.T) {
+	t.Setenv(envVarName, "say “hi”")
+
+	_, err := GetEnvASCIIOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "value of environment variable '"+envVarName+
+		"' contains a non-ASCII character at byte position 4")
+}
+
+func TestGetEnvASCIIOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvASCIIOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvSecretASCIIOrFail_DoesNotRevealValue(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(envVarName, "pässword")
+
+	_, err := GetEnvSecretASCIIOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "at byte position 1")
+	assert.NotContains(t, err.Error(), "pässword")
+	assert.NotContains(t, buf.String(), "pässword")
+}
+
+func TestGetEnvGlobOrFail_SucceedsIfWellFormed(t *testing.T) {
+	t.Setenv(envVarName, "logs/*.[lL][oO][gG]")
+
+	actualValue, err := GetEnvGlobOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "logs/*.[lL][oO][gG]", actualValue)
+}
+
+func TestGetEnvGlobOrFail_FailsIfMalformed(t *testing.T) {
+	t.Setenv(envVarName, "logs/[a-")
+
+	_, err := GetEnvGlobOrFail(envVarName)
+
+	assert.ErrorContains(t, err,
+		"value 'logs/[a-' for '"+envVarName+"' is not a valid glob pattern")
+}
+
+func TestGetEnvGlobOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvGlobOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvGlobOrDefault_SucceedsIfWellFormed(t *testing.T) {
+	t.Setenv(envVarName, "*.go")
+
+	actualValue := GetEnvGlobOrDefault(envVarName, "*")
+
+	assert.Equal(t, "*.go", actualValue)
+}
+
+func TestGetEnvGlobOrDefault_ReturnsDefaultIfMalformedOrNotSet(t *testing.T) {
+	for _, val := range []string{"", "["} {
+		t.Setenv(envVarName, val)
+
+		actualValue := GetEnvGlobOrDefault(envVarName, "*")
+
+		assert.Equal(t, "*", actualValue, val)
+	}
+}
+
+func TestGetEnvLowercaseOrFail_SucceedsIfLowercase(t *testing.T) {
+	t.Setenv(envVarName, "eu-west-1")
+
+	actualValue, err := GetEnvLowercaseOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", actualValue)
+}
+
+func TestGetEnvLowercaseOrFail_FailsInsteadOfNormalizing(t *testing.T) {
+	t.Setenv(envVarName, "EU-west-1")
+
+	_, err := GetEnvLowercaseOrFail(envVarName)
+
+	assert.EqualError(t, err,
+		"value 'EU-west-1' for '"+envVarName+"' must be entirely lowercase")
+}
+
+func TestGetEnvUppercaseOrFail_SucceedsIfUppercase(t *testing.T) {
+	t.Setenv(envVarName, "GET_1")
+
+	actualValue, err := GetEnvUppercaseOrFail(envVarName)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "GET_1", actualValue)
+}
+
+func TestGetEnvUppercaseOrFail_FailsInsteadOfNormalizing(t *testing.T) {
+	t.Setenv(envVarName, "Get")
+
+	_, err := GetEnvUppercaseOrFail(envVarName)
+
+	assert.EqualError(t, err, "value 'Get' for '"+envVarName+"' must be entirely uppercase")
+}
+
+func TestGetEnvUppercaseOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvUppercaseOrFail(envVarName)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvValidBase64OrFail_ReturnsUndecodedValue(t *testing.T) {
+	t.Setenv(envVarName, "Tm90IEVtcHR5")
+
+	actualValue, err := GetEnvValidBase64OrFail(envVarName, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tm90IEVtcHR5", actualValue)
+}
+
+func TestGetEnvValidBase64OrFail_RespectsAlphabet(t *testing.T) {
+	t.Setenv(envVarName, "-_-_")
+
+	_, err := GetEnvValidBase64OrFail(envVarName, false)
+	assert.ErrorContains(t, err, "value '-_-_' for '"+envVarName+"' is not valid base64")
+
+	actualValue, err := GetEnvValidBase64OrFail(envVarName, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "-_-_", actualValue)
+}
+
+func TestGetEnvValidBase64OrFail_FailsOnInvalidPadding(t *testing.T) {
+	t.Setenv(envVarName, "Tm90IEVtcHR")
+
+	_, err := GetEnvValidBase64OrFail(envVarName, false)
+
+	assert.ErrorContains(t, err, "is not valid base64")
+}
+
+func TestGetEnvValidBase64OrFail_MasksSecretInError(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(secretVarName, "s3cr3t!")
+
+	_, err := GetEnvValidBase64OrFail(secretVarName, false)
+
+	assert.ErrorContains(t, err, "value '**********' for '"+secretVarName+"' is not valid base64")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestGetEnvValidBase64OrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvValidBase64OrFail(envVarName, true)
+
+	assert.ErrorContains(t, err, "please set the environment variable '"+envVarName+"'")
+}
+
+func TestGetEnvPolicyCheckedOrFail_ReturnsAllowedValue(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	var checkedName, checkedValue string
+
+	actualValue, err := GetEnvPolicyCheckedOrFail(context.Background(), envVarName,
+		func(_ context.Context, name, value string) error {
+			checkedName, checkedValue = name, value
+			return nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedValue, actualValue)
+	assert.Equal(t, envVarName, checkedName)
+	assert.Equal(t, expectedValue, checkedValue)
+}
+
+func TestGetEnvPolicyCheckedOrFail_WrapsRejection(t *testing.T) {
+	t.Setenv(envVarName, "eu-west-1")
+	errRejected := errors.New("region not allowed")
+
+	_, err := GetEnvPolicyCheckedOrFail(context.Background(), envVarName,
+		func(context.Context, string, string) error { return errRejected })
+
+	assert.ErrorIs(t, err, errRejected)
+	assert.EqualError(t, err, "value 'eu-west-1' for '"+envVarName+
+		"' is rejected by policy: region not allowed")
+}
+
+func TestGetEnvPolicyCheckedOrFail_PassesContextToCheck(t *testing.T) {
+	t.Setenv(envVarName, expectedValue)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := GetEnvPolicyCheckedOrFail(ctx, envVarName,
+		func(ctx context.Context, _, _ string) error { return ctx.Err() })
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGetEnvPolicyCheckedOrFail_MasksSecretInError(t *testing.T) {
+	buf, tearDownLogging := setupLoggingAndTearDown(logrus.InfoLevel)
+	defer tearDownLogging()
+
+	t.Setenv(secretVarName, "s3cr3t!")
+
+	_, err := GetEnvPolicyCheckedOrFail(context.Background(), secretVarName,
+		func(context.Context, string, string) error { return errors.New("too weak") })
+
+	assert.ErrorContains(t, err, "value '**********' for '"+secretVarName+"' is rejected")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestGetEnvPolicyCheckedOrFail_IndeedFailsIfEnvNotSet(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	_, err := GetEnvPolicyCheckedOrFail(context.Background(), envVarName,
+		func(context.Context, string, string) error { return nil })
+
+	assert.EqualError(t, err, "please set the environment variable '"+envVarName+"'")
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ValidatedWatcher holds a value read once from an environment variable and
+// revalidates it periodically in the background, e.g. to notice that a file
+// referenced by the value was deleted while the process is running.
+type ValidatedWatcher struct {
+	envName  string
+	value    string
+	validate func(string) error
+
+	mu      sync.RWMutex
+	lastErr error
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// NewValidatedWatcher reads the environment variable with the provided name,
+// validates its value and starts a goroutine revalidating the same value
+// every interval. A warning is logged whenever the value becomes invalid and
+// an info message when it becomes valid again. Stop ends the goroutine.
+// An error is returned if interval is not positive.
+func NewValidatedWatcher(
+	envName string,
+	validate func(string) error,
+	interval time.Duration,
+) (*ValidatedWatcher, error) {
+	if interval <= 0 {
+		return nil, logEnvError(envName, fmt.Errorf(
+			"interval %v for watching '%s' is not positive", interval, envName,
+		))
+	}
+	w := &ValidatedWatcher{
+		envName:  envName,
+		value:    getenv(envName),
+		validate: validate,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.lastErr = validate(w.value)
+	if w.lastErr != nil {
+		logger.withEnv(envName).Warnf(
+			"value '%v' for '%v' is invalid: %v", w.value, envName, w.lastErr,
+		)
+	} else {
+		logValue(envName, w.value)
+	}
+	go w.run(interval)
+	return w, nil
+}
+
+// Value returns the value read from the environment variable.
+func (w *ValidatedWatcher) Value() string {
+	return w.value
+}
+
+// Err returns the result of the last validation, nil if the value was valid.
+func (w *ValidatedWatcher) Err() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.lastErr
+}
+
+// Stop ends the revalidation and waits for the goroutine to finish. It is safe
+// to call Stop more than once.
+func (w *ValidatedWatcher) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+}
+
+func (w *ValidatedWatcher) run(interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.revalidate()
+		}
+	}
+}
+
+func (w *ValidatedWatcher) revalidate() {
+	err := w.validate(w.value)
+	w.mu.Lock()
+	wasValid := w.lastErr == nil
+	w.lastErr = err
+	w.mu.Unlock()
+	switch {
+	case err != nil && wasValid:
+		logger.withEnv(w.envName).Warnf(
+			"value '%v' for '%v' became invalid: %v", w.value, w.envName, err,
+		)
+	case err == nil && !wasValid:
+		logger.withEnv(w.envName).Infof(
+			"value '%v' for '%v' is valid again", w.value, w.envName,
+		)
+	}
+}
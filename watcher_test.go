@@ -0,0 +1,87 @@
+// Copyright (c) 2023 - for information on the respective copyright owner
+// see the NOTICE file or the repository https://github.com/boschresearch/go-env-tools.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envtools
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatedWatcher_RevalidatesInBackground(t *testing.T) {
+	t.Setenv(envVarName, "/etc/app/config.yaml")
+	var exists int32
+	atomic.StoreInt32(&exists, 1)
+	validate := func(string) error {
+		if atomic.LoadInt32(&exists) == 0 {
+			return errors.New("file does not exist")
+		}
+		return nil
+	}
+
+	watcher, err := NewValidatedWatcher(envVarName, validate, time.Millisecond)
+	assert.NoError(t, err)
+	defer watcher.Stop()
+
+	assert.Equal(t, "/etc/app/config.yaml", watcher.Value())
+	assert.NoError(t, watcher.Err())
+
+	atomic.StoreInt32(&exists, 0)
+	assert.Eventually(t, func() bool { return watcher.Err() != nil }, time.Second, time.Millisecond)
+	assert.EqualError(t, watcher.Err(), "file does not exist")
+
+	atomic.StoreInt32(&exists, 1)
+	assert.Eventually(t, func() bool { return watcher.Err() == nil }, time.Second, time.Millisecond)
+}
+
+func TestValidatedWatcher_ReportsInitialValidation(t *testing.T) {
+	t.Setenv(envVarName, "")
+
+	watcher, err := NewValidatedWatcher(envVarName, func(val string) error {
+		if val == "" {
+			return errors.New("must not be empty")
+		}
+		return nil
+	}, time.Hour)
+	assert.NoError(t, err)
+	defer watcher.Stop()
+
+	assert.EqualError(t, watcher.Err(), "must not be empty")
+}
+
+func TestValidatedWatcher_StopIsIdempotent(t *testing.T) {
+	watcher, err := NewValidatedWatcher(
+		envVarName, func(string) error { return nil }, time.Millisecond,
+	)
+	assert.NoError(t, err)
+
+	watcher.Stop()
+	watcher.Stop()
+}
+
+func TestNewValidatedWatcher_FailsOnNonPositiveInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		watcher, err := NewValidatedWatcher(
+			envVarName, func(string) error { return nil }, interval,
+		)
+
+		assert.Nil(t, watcher)
+		assert.ErrorContains(t, err, "for watching '"+envVarName+"' is not positive")
+	}
+}